@@ -0,0 +1,110 @@
+package main
+
+import (
+	"compress/gzip"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/danilobml/motivate/internal/formats"
+	"github.com/danilobml/motivate/internal/helpers"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+// runExport implements `motivate export`: it writes the stored quotes to a file,
+// or stdout, in one of the seed formats so they can be backed up or moved to other storage.
+func runExport(args []string) (err error) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	storage := flags.String("storage", helpers.GetenvString("STORAGE", ""), "Quote storage to export from, sqlite:// or file://. Defaults to the STORAGE env variable.")
+	output := flags.String("output", "-", "File to write, or - for stdout. A .gz suffix compresses it.")
+	formatName := flags.String("format", "", "json, ndjson, csv, yaml or fortune. Defaults to the --output extension, or json. A fortune file written uncompressed to --output gets a strfile index next to it (<output>.dat).")
+	source := flags.String("source", "", "Only export quotes from this source, e.g. zenquotes.")
+	flags.Parse(args)
+
+	format := formats.JSON
+	if *formatName != "" {
+		format, err = formats.ParseFormat(*formatName)
+		if err != nil {
+			return err
+		}
+	} else if extFormat, ok := formats.FromExtension(*output); ok {
+		format = extFormat
+	}
+
+	quotesRepo, err := repositories.OpenQuoteRepositoryReadOnly(*storage)
+	if err != nil {
+		return fmt.Errorf("failed to open quote storage: %w", err)
+	}
+	if closer, ok := quotesRepo.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.Join(err, file.Close())
+			if err != nil {
+				os.Remove(*output)
+			}
+		}()
+		out = file
+	}
+
+	compressed := strings.HasSuffix(strings.ToLower(*output), ".gz")
+	if compressed {
+		gz := gzip.NewWriter(out)
+		defer func() {
+			err = errors.Join(err, gz.Close())
+		}()
+		out = gz
+	}
+
+	writer, err := formats.NewWriter(out, format)
+	if err != nil {
+		return err
+	}
+
+	written, err := services.NewQuoteService(quotesRepo).WriteQuotes(writer, services.QuoteFilter{Source: *source})
+	if err != nil {
+		return err
+	}
+
+	// strfile offsets point into the uncompressed file, so only a plain file gets an index.
+	if fortune, ok := writer.(*formats.FortuneWriter); ok && *output != "-" && !compressed {
+		if err := writeFortuneIndex(fortune, *output+".dat"); err != nil {
+			return err
+		}
+	}
+
+	if *output != "-" {
+		log.Printf("Exported %d quotes to %s.", written, *output)
+	}
+
+	return nil
+}
+
+func writeFortuneIndex(fortune *formats.FortuneWriter, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = fortune.WriteIndex(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+
+	return err
+}
@@ -1,48 +1,148 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
+	"io"
 	"log"
-	"path/filepath"
+	"os"
+	"slices"
+	"strings"
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 
+	"github.com/danilobml/motivate/internal/formats"
 	"github.com/danilobml/motivate/internal/handlers"
+	"github.com/danilobml/motivate/internal/helpers"
 	"github.com/danilobml/motivate/internal/httpx"
 	"github.com/danilobml/motivate/internal/repositories"
 	"github.com/danilobml/motivate/internal/services"
+	"github.com/danilobml/motivate/internal/templates"
 )
 
 func main() {
 	godotenv.Load()
 
-	seedFilePath := flag.String("seed-file", "", "Error: No file path provided. Insert the path to a json file containing quotes. The quotes database will be seeded from it.")
-	seedApi := flag.Bool("seed-api", false, "If set, will access zenquotes API and get quotes. The quotes database will be seeded from it.")
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			log.Fatalf("Error exporting quotes: %s", err.Error())
+		}
+		return
+	}
+
+	seedFilePath := flag.String("seed-file", "", "Path to a file containing quotes (.json, .ndjson, .csv, .yaml, .fortune, Kindle's My Clippings.txt or a Goodreads quotes CSV, optionally .gz). The quotes database will be seeded from it.")
+	seedDryRun := flag.Bool("seed-dry-run", false, "Validate --seed-file against the current storage, print the report as JSON and exit without storing anything. Exits with status 1 if any record is invalid.")
+	seedStrict := flag.Bool("seed-strict", false, "Abort seeding from --seed-file at the first invalid record. Nothing is stored unless every record is valid.")
+	var seedFormat formats.Format
+	flag.Func("seed-format", "Format of --seed-file when its name does not tell: json, ndjson, csv, yaml, fortune, kindle or goodreads.", func(name string) error {
+		format, err := formats.ParseFormat(name)
+		seedFormat = format
+		return err
+	})
+	storage := flag.String("storage", helpers.GetenvString("STORAGE", "memory"), "Where quotes are stored: \"memory\", \"sqlite://path/to/quotes.db\" or \"file://path/to/quotes.json\". Defaults to the STORAGE env variable, or memory.")
+	seedApi := flag.Bool("seed-api", false, "If set, will access zenquotes API and get quotes. The quotes database will be seeded from it. Same as --source zenquotes.")
+	sourceNames := []string{}
+	flag.Func("source", "Name of an external quote API to seed from: "+strings.Join(repositories.QuoteSourceNames(), ", ")+". Can be repeated.", func(name string) error {
+		_, err := repositories.NewQuoteSourceByName(name)
+		if err != nil {
+			return err
+		}
+		sourceNames = append(sourceNames, name)
+		return nil
+	})
+	templatesDir := flag.String("templates-dir", helpers.GetenvString("TEMPLATES_DIR", ""), "Directory with share email templates (share_subject.txt, share.txt, share.html) replacing the built-in ones. Defaults to the TEMPLATES_DIR env variable.")
+	syncInterval := flag.Duration("sync-interval", helpers.GetenvDuration("SYNC_INTERVAL", 0), "How often to refresh quotes from the configured sources in the background, e.g. 6h. Defaults to the SYNC_INTERVAL env variable (seconds); 0 disables it.")
 	flag.Parse()
 
-	quotesRepo := repositories.NewInMemoryQuoteRepository()
+	if *seedApi && !slices.Contains(sourceNames, "zenquotes") {
+		sourceNames = append(sourceNames, "zenquotes")
+	}
+
+	if *seedDryRun {
+		if *seedFilePath == "" {
+			log.Fatal("--seed-dry-run requires --seed-file")
+		}
+		os.Exit(checkSeedFile(*storage, *seedFilePath, services.SeedOptions{DryRun: true, Strict: *seedStrict, Format: seedFormat}))
+	}
+
+	quotesRepo, err := repositories.NewQuoteRepositoryFromUrl(*storage)
+	if err != nil {
+		log.Fatalf("Error opening quote storage: %s", err.Error())
+	}
+	if closer, ok := quotesRepo.(io.Closer); ok {
+		defer closer.Close()
+	}
+
 	quotesService := services.NewQuoteService(quotesRepo)
 	mailService := services.NewMailService()
-	quotesRouter := handlers.NewQuotesRouter(quotesService, mailService)
+	shareTemplates, err := templates.NewShareTemplates(*templatesDir)
+	if err != nil {
+		log.Fatalf("Error loading email templates: %s", err.Error())
+	}
+	quotesRouter := handlers.NewQuotesRouter(quotesService, mailService, shareTemplates)
 
-	zenRepo := repositories.NewZenQuoteRepository("https://zenquotes.io/api/quotes")
-	zenService := services.NewZenQuoteService(quotesRepo, zenRepo)
+	sourceService := services.NewSourceService(quotesRepo)
 
-	if *seedFilePath != "" && filepath.Ext(*seedFilePath) != ".json" {
-		log.Println("No valid json seed file path given. The API will initialize unseeded.")
-	} else if *seedFilePath != "" {
-		err := quotesService.SeedDbFromFile(*seedFilePath)
+	if *seedFilePath != "" {
+		_, err := quotesService.SeedDbFromFile(*seedFilePath, services.SeedOptions{Strict: *seedStrict, Format: seedFormat})
+		if err != nil && *seedStrict {
+			log.Fatalf("Error seeding DB: %s. Nothing was stored.", err.Error())
+		}
 		if err != nil {
 			log.Printf("Error seeding DB: %s. The API will initialize unseeded.", err.Error())
 		}
 	}
 
-	if *seedApi {
-		err := zenService.SeedDbFromApi()
+	sources := []repositories.QuoteSource{}
+	for _, name := range sourceNames {
+		source, _ := repositories.NewQuoteSourceByName(name)
+		sources = append(sources, source)
+	}
+
+	syncService := services.NewSyncService(sourceService, sources, *syncInterval)
+	syncService.SyncNow(context.Background())
+	syncService.Start()
+
+	importJobService := services.NewImportJobService(quotesService, helpers.GetenvInt("IMPORT_CONCURRENCY", 2), helpers.GetenvDuration("IMPORT_JOB_RETENTION", 3600))
+	importRouter := handlers.NewImportRouter(importJobService)
+
+	adminRouter := handlers.NewAdminRouter(syncService)
+
+	httpx.NewServer(handlers.RegisterRoutes(quotesRouter, importRouter, adminRouter), syncService.Stop, importJobService.Stop)
+}
+
+// checkSeedFile validates a seed file against the current storage without
+// storing anything and prints the report. The storage is opened read-only, so
+// a file:// store is not compacted and a sqlite:// one is not migrated. It
+// returns the process exit code.
+func checkSeedFile(storage, path string, options services.SeedOptions) int {
+	var quotesRepo repositories.QuoteRepository = repositories.NewInMemoryQuoteRepository()
+	if scheme, _, _ := strings.Cut(storage, "://"); scheme != "" && scheme != "memory" {
+		readOnly, err := repositories.OpenQuoteRepositoryReadOnly(storage)
 		if err != nil {
-			log.Printf("Error seeding DB: %s. The API will initialize unseeded.", err.Error())
+			log.Printf("Error opening quote storage: %s", err.Error())
+			return 1
 		}
+		if closer, ok := readOnly.(io.Closer); ok {
+			defer closer.Close()
+		}
+		quotesRepo = readOnly
 	}
 
-	httpx.NewServer(handlers.RegisterRoutes(quotesRouter))
+	report, err := services.NewQuoteService(quotesRepo).SeedDbFromFile(path, options)
+	if err != nil {
+		log.Printf("Seed file check failed: %s", err.Error())
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+
+	if report.Invalid > 0 {
+		return 1
+	}
+	return 0
 }
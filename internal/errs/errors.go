@@ -8,4 +8,18 @@ var ErrAlreadyExists = errors.New("entry already exists")
 
 var ErrEmpty = errors.New("no entries exist")
 
-var ErrMailServiceDisabled = errors.New("one or more email environment variables are missing")
\ No newline at end of file
+var ErrNoMatch = errors.New("no entries match the given filters")
+
+var ErrClosed = errors.New("repository is closed")
+
+var ErrMailServiceDisabled = errors.New("one or more email environment variables are missing")
+
+var ErrRateLimited = errors.New("rate limited by upstream")
+
+var ErrNetwork = errors.New("network failure reaching upstream")
+
+var ErrBadUpstreamResponse = errors.New("unexpected upstream response")
+
+var ErrInvalidQuote = errors.New("invalid quote")
+
+var ErrJobFinished = errors.New("job has already finished")
@@ -0,0 +1,142 @@
+package formats
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+// csvColumns maps accepted header names, compared case-insensitively, to quote fields.
+var csvColumns = map[string]string{
+	"id":          "id",
+	"text":        "text",
+	"quote":       "text",
+	"content":     "text",
+	"author":      "author",
+	"by":          "author",
+	"tags":        "tags",
+	"tag":         "tags",
+	"categories":  "tags",
+	"language":    "language",
+	"lang":        "language",
+	"book":        "book",
+	"title":       "book",
+	"external_id": "external_id",
+	"source":      "source",
+	"batch_id":    "batch_id",
+	"created_by":  "created_by",
+	"created_at":  "created_at",
+	"updated_at":  "updated_at",
+}
+
+// csvReader reads quotes from a CSV file whose first row names the columns.
+// Unknown columns are ignored; tags are separated by commas, semicolons or pipes.
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	record  int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return &csvReader{reader: reader}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := csvColumns[name]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["text"]; !ok {
+		return nil, errors.New("csv header has no text, quote or content column")
+	}
+
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (cr *csvReader) Next() (models.Quote, error) {
+	if cr.columns == nil {
+		return models.Quote{}, io.EOF
+	}
+
+	row, err := cr.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return models.Quote{}, io.EOF
+	}
+	cr.record++
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return models.Quote{}, &RecordError{Record: cr.record, Err: err}
+	}
+	if err != nil {
+		return models.Quote{}, err
+	}
+
+	createdAt, err := parseTime(cr.field(row, "created_at"))
+	if err != nil {
+		return models.Quote{}, &RecordError{Record: cr.record, Err: fmt.Errorf("invalid created_at: %w", err)}
+	}
+	updatedAt, err := parseTime(cr.field(row, "updated_at"))
+	if err != nil {
+		return models.Quote{}, &RecordError{Record: cr.record, Err: fmt.Errorf("invalid updated_at: %w", err)}
+	}
+
+	return models.Quote{
+		Id:         cr.field(row, "id"),
+		Text:       cr.field(row, "text"),
+		Author:     cr.field(row, "author"),
+		Tags:       splitTags(cr.field(row, "tags")),
+		Language:   cr.field(row, "language"),
+		Book:       cr.field(row, "book"),
+		Source:     cr.field(row, "source"),
+		ExternalId: cr.field(row, "external_id"),
+		BatchId:    cr.field(row, "batch_id"),
+		CreatedBy:  cr.field(row, "created_by"),
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+	}, nil
+}
+
+func (cr *csvReader) field(row []string, name string) string {
+	i, ok := cr.columns[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// parseTime reads the RFC 3339 timestamps csvWriter writes; an empty value is the zero time.
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' || r == '|' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
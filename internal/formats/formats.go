@@ -0,0 +1,180 @@
+// Package formats reads (and writes) quotes in the file formats used for seeding
+// and exporting: JSON arrays, newline-delimited JSON, CSV, YAML and fortune(6)
+// files, optionally gzip-compressed.
+package formats
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+type Format string
+
+const (
+	JSON    Format = "json"
+	NDJSON  Format = "ndjson"
+	CSV     Format = "csv"
+	YAML    Format = "yaml"
+	Fortune Format = "fortune"
+	// Kindle is the "My Clippings.txt" file of a Kindle e-reader.
+	Kindle Format = "kindle"
+	// Goodreads is a CSV of quotes saved on Goodreads.
+	Goodreads Format = "goodreads"
+)
+
+var extensions = map[string]Format{
+	".json":    JSON,
+	".ndjson":  NDJSON,
+	".jsonl":   NDJSON,
+	".csv":     CSV,
+	".yaml":    YAML,
+	".yml":     YAML,
+	".fortune": Fortune,
+}
+
+// sniffBytes is how much of the input is inspected when the file name does not tell the format.
+const sniffBytes = 512
+
+// RecordError reports a single record that could not be decoded. Reading can
+// continue past it; any other error from Next ends the input.
+type RecordError struct {
+	Record int
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %d: %s", e.Record, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// QuoteReader yields the quotes of a file one at a time. Next returns io.EOF after the last one.
+type QuoteReader interface {
+	Next() (models.Quote, error)
+}
+
+// FromExtension maps a file name to its format, ignoring a trailing .gz.
+func FromExtension(name string) (Format, bool) {
+	name = strings.TrimSuffix(strings.ToLower(name), ".gz")
+	format, ok := extensions[filepath.Ext(name)]
+	return format, ok
+}
+
+// FromName is FromExtension, but also recognises the file names Kindle
+// ("My Clippings.txt") and Goodreads exports use.
+func FromName(name string) (Format, bool) {
+	base := strings.TrimSuffix(strings.ToLower(filepath.Base(name)), ".gz")
+	switch {
+	case strings.Contains(base, "clippings") && strings.HasSuffix(base, ".txt"):
+		return Kindle, true
+	case strings.Contains(base, "goodreads") && strings.HasSuffix(base, ".csv"):
+		return Goodreads, true
+	}
+	return FromExtension(base)
+}
+
+// ParseFormat accepts a format name such as "csv", "yml" or "kindle".
+func ParseFormat(name string) (Format, error) {
+	name = strings.ToLower(name)
+	if format, ok := FromExtension("." + name); ok {
+		return format, nil
+	}
+	if format := Format(name); format == Kindle || format == Goodreads {
+		return format, nil
+	}
+	return "", fmt.Errorf("unsupported format %q", name)
+}
+
+// Sniff guesses the format from the start of the (uncompressed) content.
+func Sniff(head []byte) Format {
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	head = bytes.TrimLeft(head, " \t\r\n")
+
+	switch {
+	case bytes.Contains(head, []byte("\n"+kindleSeparator)):
+		return Kindle
+	case bytes.HasPrefix(head, []byte("%\n")), bytes.Contains(head, []byte("\n%\n")), bytes.Contains(head, []byte("\n%\r\n")):
+		return Fortune
+	case bytes.HasPrefix(head, []byte("[")):
+		return JSON
+	case bytes.HasPrefix(head, []byte("{")):
+		return NDJSON
+	case bytes.HasPrefix(head, []byte("-")), bytes.HasPrefix(head, []byte("#")):
+		return YAML
+	default:
+		return CSV
+	}
+}
+
+// NewReader decompresses r if it is gzipped and picks a reader by name (see
+// FromName), falling back to sniffing the content when the name does not tell.
+func NewReader(r io.Reader, name string) (QuoteReader, error) {
+	buffered, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+
+	format, ok := FromName(name)
+	if !ok {
+		head, _ := buffered.Peek(sniffBytes)
+		format = Sniff(head)
+	}
+
+	return NewFormatReader(buffered, format)
+}
+
+// NewReaderAs is NewReader for input whose format is known.
+func NewReaderAs(r io.Reader, format Format) (QuoteReader, error) {
+	buffered, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFormatReader(buffered, format)
+}
+
+func decompress(r io.Reader) (*bufio.Reader, error) {
+	buffered := bufio.NewReader(r)
+
+	magic, _ := buffered.Peek(2)
+	if !bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		return buffered, nil
+	}
+
+	gz, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzip data: %w", err)
+	}
+	return bufio.NewReader(gz), nil
+}
+
+// NewFormatReader reads uncompressed quotes in the given format.
+func NewFormatReader(r io.Reader, format Format) (QuoteReader, error) {
+	switch format {
+	case JSON:
+		return newJSONReader(r), nil
+	case NDJSON:
+		return newNDJSONReader(r), nil
+	case CSV:
+		return newCSVReader(r)
+	case YAML:
+		return newYAMLReader(r)
+	case Fortune:
+		return newFortuneReader(r), nil
+	case Kindle:
+		return newKindleReader(r)
+	case Goodreads:
+		return newGoodreadsReader(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
@@ -0,0 +1,158 @@
+package formats
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+// fortuneAttribution matches the "-- Author" line that closes many fortune(6) entries.
+var fortuneAttribution = regexp.MustCompile(`^\s*(?:--|—|―)\s*(\S.*?)\s*$`)
+
+func isFortuneDelimiter(line string) bool {
+	line = strings.TrimRight(line, "\r")
+	return line == "%" || line == "%%"
+}
+
+// fortuneReader reads fortune(6) files: entries separated by lines holding only "%".
+// A last line starting with "--" is taken as the author.
+type fortuneReader struct {
+	scanner *bufio.Scanner
+	done    bool
+}
+
+func newFortuneReader(r io.Reader) *fortuneReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	return &fortuneReader{scanner: scanner}
+}
+
+func (fr *fortuneReader) Next() (models.Quote, error) {
+	for !fr.done {
+		lines := []string{}
+		for {
+			if !fr.scanner.Scan() {
+				if err := fr.scanner.Err(); err != nil {
+					return models.Quote{}, err
+				}
+				fr.done = true
+				break
+			}
+			line := fr.scanner.Text()
+			if isFortuneDelimiter(line) {
+				break
+			}
+			lines = append(lines, strings.TrimRight(line, "\r"))
+		}
+
+		if quote, ok := parseFortune(lines); ok {
+			return quote, nil
+		}
+	}
+
+	return models.Quote{}, io.EOF
+}
+
+func parseFortune(lines []string) (models.Quote, bool) {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return models.Quote{}, false
+	}
+
+	var quote models.Quote
+	if match := fortuneAttribution.FindStringSubmatch(lines[len(lines)-1]); match != nil && len(lines) > 1 {
+		quote.Author = match[1]
+		lines = lines[:len(lines)-1]
+	}
+	quote.Text = strings.TrimSpace(strings.Join(lines, "\n"))
+
+	return quote, quote.Text != ""
+}
+
+// strfile(8) header constants, as written by fortune-mod.
+const (
+	strfileVersion   = 2
+	strfileDelimiter = '%'
+)
+
+// FortuneWriter writes a fortune(6) file and keeps what is needed to write its
+// strfile(8) .dat index, so the output can be used by fortune directly.
+type FortuneWriter struct {
+	w        io.Writer
+	offset   uint32
+	offsets  []uint32
+	longest  uint32
+	shortest uint32
+}
+
+func NewFortuneWriter(w io.Writer) *FortuneWriter {
+	return &FortuneWriter{w: w, offsets: []uint32{0}}
+}
+
+func (fw *FortuneWriter) Write(quote models.Quote) error {
+	entry := strings.TrimSpace(quote.Text) + "\n"
+	if quote.Author != "" && quote.Author != "Unknown" {
+		entry += "\t\t-- " + quote.Author + "\n"
+	}
+
+	// A line holding only "%" inside the text would split the entry on reading.
+	lines := strings.Split(entry, "\n")
+	for i, line := range lines {
+		if isFortuneDelimiter(line) {
+			lines[i] = " " + line
+		}
+	}
+	entry = strings.Join(lines, "\n")
+
+	if _, err := io.WriteString(fw.w, entry+"%\n"); err != nil {
+		return err
+	}
+
+	length := uint32(len(entry))
+	if len(fw.offsets) == 1 {
+		fw.longest, fw.shortest = length, length
+	} else {
+		fw.longest = max(fw.longest, length)
+		fw.shortest = min(fw.shortest, length)
+	}
+	fw.offset += length + uint32(len("%\n"))
+	fw.offsets = append(fw.offsets, fw.offset)
+
+	return nil
+}
+
+func (fw *FortuneWriter) Close() error {
+	return nil
+}
+
+// WriteIndex writes the strfile .dat index for the entries written so far: a
+// big-endian header (version, count, longest, shortest, flags, delimiter)
+// followed by the offset of each entry and of the end of the file.
+func (fw *FortuneWriter) WriteIndex(w io.Writer) error {
+	header := struct {
+		Version  uint32
+		Count    uint32
+		Longest  uint32
+		Shortest uint32
+		Flags    uint32
+		Stuff    [4]byte
+	}{
+		Version:  strfileVersion,
+		Count:    uint32(len(fw.offsets) - 1),
+		Longest:  fw.longest,
+		Shortest: fw.shortest,
+		Stuff:    [4]byte{strfileDelimiter},
+	}
+
+	if err := binary.Write(w, binary.BigEndian, header); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.BigEndian, fw.offsets)
+}
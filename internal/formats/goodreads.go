@@ -0,0 +1,40 @@
+package formats
+
+import (
+	"io"
+	"strings"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+// goodreadsReader reads a Goodreads saved quotes CSV (Quote, Author, Book, Tags, Likes ...).
+// Goodreads wraps the text in curly quotes and sometimes appends "― Author, Book"; both are removed.
+type goodreadsReader struct {
+	csv *csvReader
+}
+
+func newGoodreadsReader(r io.Reader) (*goodreadsReader, error) {
+	reader, err := newCSVReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &goodreadsReader{csv: reader}, nil
+}
+
+func (gr *goodreadsReader) Next() (models.Quote, error) {
+	quote, err := gr.csv.Next()
+	if err != nil {
+		return quote, err
+	}
+
+	text := quote.Text
+	if i := strings.LastIndex(text, "―"); i > 0 {
+		text = text[:i]
+	}
+	quote.Text = strings.Trim(strings.TrimSpace(text), "“”\"")
+	quote.Author = strings.TrimRight(quote.Author, ", ")
+	quote.Source = models.SourceGoodreads
+
+	return quote, nil
+}
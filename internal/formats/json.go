@@ -0,0 +1,95 @@
+package formats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+// jsonReader streams the elements of a JSON array without holding the whole array in memory.
+type jsonReader struct {
+	decoder *json.Decoder
+	started bool
+	record  int
+}
+
+func newJSONReader(r io.Reader) *jsonReader {
+	return &jsonReader{decoder: json.NewDecoder(r)}
+}
+
+func (jr *jsonReader) Next() (models.Quote, error) {
+	if !jr.started {
+		token, err := jr.decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return models.Quote{}, io.EOF
+		}
+		if err != nil {
+			return models.Quote{}, err
+		}
+		if token != json.Delim('[') {
+			return models.Quote{}, errors.New("json seed file must contain an array of quotes")
+		}
+		jr.started = true
+	}
+
+	if !jr.decoder.More() {
+		if _, err := jr.decoder.Token(); err != nil {
+			return models.Quote{}, err
+		}
+		return models.Quote{}, io.EOF
+	}
+
+	jr.record++
+	var quote models.Quote
+	err := jr.decoder.Decode(&quote)
+
+	// A value of the wrong type is consumed whole, so the array can be read on past it.
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return models.Quote{}, &RecordError{Record: jr.record, Err: err}
+	}
+	if err != nil {
+		return models.Quote{}, fmt.Errorf("record %d: %w", jr.record, err)
+	}
+
+	return quote, nil
+}
+
+// ndjsonReader reads one JSON object per line. Blank lines are skipped.
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	record  int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	return &ndjsonReader{scanner: scanner}
+}
+
+func (nr *ndjsonReader) Next() (models.Quote, error) {
+	for nr.scanner.Scan() {
+		line := nr.scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		nr.record++
+		var quote models.Quote
+		if err := json.Unmarshal(line, &quote); err != nil {
+			return models.Quote{}, &RecordError{Record: nr.record, Err: err}
+		}
+		return quote, nil
+	}
+
+	if err := nr.scanner.Err(); err != nil {
+		return models.Quote{}, err
+	}
+	return models.Quote{}, io.EOF
+}
@@ -0,0 +1,113 @@
+package formats
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+const (
+	kindleSeparator     = "=========="
+	kindleClippingLimit = "<You have reached the clipping limit for this item>"
+)
+
+var (
+	// kindleTitle splits "Meditations (Marcus Aurelius)" into book and author; titles may contain parentheses too.
+	kindleTitle    = regexp.MustCompile(`^(.*\S)\s*\(([^()]*)\)\s*$`)
+	kindleLocation = regexp.MustCompile(`(?i)\b(?:location|loc\.)\s+(\d+)(?:-(\d+))?`)
+)
+
+type kindleClipping struct {
+	quote      models.Quote
+	start, end int
+}
+
+// overlaps reports whether c is a re-highlight of other: the same passage of the
+// same book, selected again with a slightly different extent.
+func (c kindleClipping) overlaps(other kindleClipping) bool {
+	if c.quote.Book != other.quote.Book || c.quote.Author != other.quote.Author {
+		return false
+	}
+	if c.start > 0 && other.start > 0 && (c.start > other.end || other.start > c.end) {
+		return false
+	}
+	return strings.Contains(c.quote.Text, other.quote.Text) || strings.Contains(other.quote.Text, c.quote.Text)
+}
+
+// newKindleReader parses a Kindle "My Clippings.txt". Only highlights are kept;
+// notes and bookmarks are skipped. Kindle appends a new clipping whenever a
+// highlight is changed, so an overlapping clipping replaces the earlier one.
+// The file is small and must be seen whole to do that, so it is read up front.
+func newKindleReader(r io.Reader) (QuoteReader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	clippings := []kindleClipping{}
+	for _, entry := range strings.Split(string(data), kindleSeparator) {
+		clipping, ok := parseKindleClipping(entry)
+		if !ok {
+			continue
+		}
+
+		replaced := false
+		for i := range clippings {
+			if clipping.overlaps(clippings[i]) {
+				clippings[i] = clipping
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			clippings = append(clippings, clipping)
+		}
+	}
+
+	quotes := make([]models.Quote, len(clippings))
+	for i, clipping := range clippings {
+		quotes[i] = clipping.quote
+	}
+
+	return NewSliceReader(quotes), nil
+}
+
+func parseKindleClipping(entry string) (kindleClipping, bool) {
+	lines := strings.Split(strings.Trim(entry, "\n"), "\n")
+	if len(lines) < 3 {
+		return kindleClipping{}, false
+	}
+
+	title := strings.TrimSpace(strings.TrimPrefix(lines[0], "\ufeff"))
+	meta := strings.ToLower(lines[1])
+	if !strings.HasPrefix(meta, "- ") || strings.Contains(meta, "bookmark") || strings.Contains(meta, "note") {
+		return kindleClipping{}, false
+	}
+
+	text := strings.TrimSpace(strings.Join(lines[2:], "\n"))
+	if text == "" || text == kindleClippingLimit {
+		return kindleClipping{}, false
+	}
+
+	clipping := kindleClipping{quote: models.Quote{Text: text, Book: title, Source: models.SourceKindle}}
+	if match := kindleTitle.FindStringSubmatch(title); match != nil {
+		clipping.quote.Book = match[1]
+		clipping.quote.Author = strings.TrimSpace(match[2])
+	}
+	if match := kindleLocation.FindStringSubmatch(lines[1]); match != nil {
+		clipping.start, _ = strconv.Atoi(match[1])
+		clipping.end = clipping.start
+		clipping.quote.ExternalId = match[1]
+		if match[2] != "" {
+			clipping.end, _ = strconv.Atoi(match[2])
+			clipping.quote.ExternalId += "-" + match[2]
+		}
+	}
+
+	return clipping, true
+}
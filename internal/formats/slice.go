@@ -0,0 +1,27 @@
+package formats
+
+import (
+	"io"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+type sliceReader struct {
+	quotes []models.Quote
+}
+
+// NewSliceReader yields quotes already in memory, e.g. fetched from an API.
+func NewSliceReader(quotes []models.Quote) QuoteReader {
+	return &sliceReader{quotes: quotes}
+}
+
+func (sr *sliceReader) Next() (models.Quote, error) {
+	if len(sr.quotes) == 0 {
+		return models.Quote{}, io.EOF
+	}
+
+	quote := sr.quotes[0]
+	sr.quotes = sr.quotes[1:]
+
+	return quote, nil
+}
@@ -0,0 +1,168 @@
+package formats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+// QuoteWriter writes quotes one at a time in a format the readers accept back.
+// Close completes the output; it does not close the underlying writer.
+type QuoteWriter interface {
+	Write(quote models.Quote) error
+	Close() error
+}
+
+var contentTypes = map[Format]string{
+	JSON:    "application/json",
+	NDJSON:  "application/x-ndjson",
+	CSV:     "text/csv; charset=utf-8",
+	YAML:    "application/yaml",
+	Fortune: "text/plain; charset=utf-8",
+}
+
+// ContentType is the MIME type served for format, or "" if quotes cannot be written in it.
+func ContentType(format Format) string {
+	return contentTypes[format]
+}
+
+func NewWriter(w io.Writer, format Format) (QuoteWriter, error) {
+	switch format {
+	case JSON:
+		return &jsonWriter{w: w}, nil
+	case NDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	case CSV:
+		return &csvWriter{writer: csv.NewWriter(w)}, nil
+	case YAML:
+		return &yamlWriter{w: w}, nil
+	case Fortune:
+		return NewFortuneWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// jsonWriter writes a JSON array one element at a time, so large collections are never held in memory twice.
+type jsonWriter struct {
+	w       io.Writer
+	written int
+}
+
+func (jw *jsonWriter) Write(quote models.Quote) error {
+	data, err := json.Marshal(quote)
+	if err != nil {
+		return err
+	}
+
+	prefix := ",\n  "
+	if jw.written == 0 {
+		prefix = "[\n  "
+	}
+	jw.written++
+
+	_, err = fmt.Fprintf(jw.w, "%s%s", prefix, data)
+	return err
+}
+
+func (jw *jsonWriter) Close() error {
+	if jw.written == 0 {
+		_, err := io.WriteString(jw.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(jw.w, "\n]\n")
+	return err
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (nw *ndjsonWriter) Write(quote models.Quote) error {
+	return nw.encoder.Encode(quote)
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nil
+}
+
+var csvHeader = []string{"id", "text", "author", "tags", "language", "book", "source", "external_id", "batch_id", "created_by", "created_at", "updated_at"}
+
+// csvWriter writes the header on the first quote; tags are joined with semicolons.
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (cw *csvWriter) Write(quote models.Quote) error {
+	if !cw.headerWritten {
+		if err := cw.writer.Write(csvHeader); err != nil {
+			return err
+		}
+		cw.headerWritten = true
+	}
+
+	return cw.writer.Write([]string{
+		quote.Id,
+		quote.Text,
+		quote.Author,
+		strings.Join(quote.Tags, ";"),
+		quote.Language,
+		quote.Book,
+		quote.Source,
+		quote.ExternalId,
+		quote.BatchId,
+		quote.CreatedBy,
+		formatTime(quote.CreatedAt),
+		formatTime(quote.UpdatedAt),
+	})
+}
+
+func (cw *csvWriter) Close() error {
+	if !cw.headerWritten {
+		if err := cw.writer.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// yamlWriter emits each quote as a one-element sequence; concatenated, they form a single sequence.
+type yamlWriter struct {
+	w       io.Writer
+	written int
+}
+
+func (yw *yamlWriter) Write(quote models.Quote) error {
+	data, err := yaml.Marshal([]yamlQuote{yamlQuote(quote)})
+	if err != nil {
+		return err
+	}
+	yw.written++
+
+	_, err = yw.w.Write(data)
+	return err
+}
+
+func (yw *yamlWriter) Close() error {
+	if yw.written == 0 {
+		_, err := io.WriteString(yw.w, "[]\n")
+		return err
+	}
+	return nil
+}
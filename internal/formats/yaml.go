@@ -0,0 +1,45 @@
+package formats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+// yamlQuote mirrors models.Quote field for field, with YAML names.
+type yamlQuote struct {
+	Id         string    `yaml:"id"`
+	Text       string    `yaml:"text"`
+	Author     string    `yaml:"author"`
+	Tags       []string  `yaml:"tags,omitempty,flow"`
+	Language   string    `yaml:"language,omitempty"`
+	Book       string    `yaml:"book,omitempty"`
+	Source     string    `yaml:"source,omitempty"`
+	ExternalId string    `yaml:"external_id,omitempty"`
+	BatchId    string    `yaml:"batch_id,omitempty"`
+	CreatedBy  string    `yaml:"created_by,omitempty"`
+	CreatedAt  time.Time `yaml:"created_at,omitempty"`
+	UpdatedAt  time.Time `yaml:"updated_at,omitempty"`
+}
+
+// newYAMLReader reads a YAML sequence of quotes. YAML has no streaming form worth
+// supporting here, so the document is decoded up front.
+func newYAMLReader(r io.Reader) (QuoteReader, error) {
+	yamlQuotes := []yamlQuote{}
+	err := yaml.NewDecoder(r).Decode(&yamlQuotes)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read yaml: %w", err)
+	}
+
+	quotes := make([]models.Quote, len(yamlQuotes))
+	for i, quote := range yamlQuotes {
+		quotes[i] = models.Quote(quote)
+	}
+
+	return NewSliceReader(quotes), nil
+}
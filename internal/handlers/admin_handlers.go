@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/danilobml/motivate/internal/services"
+)
+
+type AdminRouter struct {
+	syncService *services.SyncService
+}
+
+func NewAdminRouter(syncService *services.SyncService) *AdminRouter {
+	return &AdminRouter{
+		syncService: syncService,
+	}
+}
+
+func (ar *AdminRouter) getSyncStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ar.syncService.Status())
+}
@@ -1,35 +1,68 @@
 package handlers
 
 import (
+	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/formats"
 	"github.com/danilobml/motivate/internal/helpers"
+	"github.com/danilobml/motivate/internal/models"
 	"github.com/danilobml/motivate/internal/services"
+	"github.com/danilobml/motivate/internal/templates"
 )
 
+const clientCookieName = "motivate_client"
+
 type QuotesRouter struct {
-	quotesService *services.QuoteService
-	mailService   services.Mailer
+	quotesService  *services.QuoteService
+	mailService    services.Mailer
+	shareTemplates *templates.ShareTemplates
 }
 
 type NewQuoteRequest struct {
-	Text   string `json:"text" validate:"required,min=1,max=512"`
-	Author string `json:"author" validate:"max=128"`
+	Text   string   `json:"text" validate:"required,min=1,max=512"`
+	Author string   `json:"author" validate:"max=128"`
+	Tags   []string `json:"tags" validate:"max=16,dive,required,max=32"`
+}
+
+type UpdateQuoteRequest struct {
+	Text   *string   `json:"text" validate:"omitempty,min=1,max=512"`
+	Author *string   `json:"author" validate:"omitempty,max=128"`
+	Tags   *[]string `json:"tags" validate:"omitempty,max=16,dive,required,max=32"`
 }
 
 type EmailRequest struct {
-	To []string `json:"to" validate:"required,min=1,dive,required,email"`
+	To         []string `json:"to" validate:"required,min=1,max=5,dive,required,email"`
+	QuoteId    string   `json:"quote_id" validate:"max=64"`
+	SenderName string   `json:"sender_name" validate:"max=64"`
+	ReplyTo    string   `json:"reply_to" validate:"omitempty,email"`
+	Message    string   `json:"message" validate:"max=1000"`
+}
+
+type SharePreviewRequest struct {
+	QuoteId    string `validate:"max=64"`
+	SenderName string `validate:"max=64"`
+	Message    string `validate:"max=1000"`
+	Format     string `validate:"omitempty,oneof=html text json"`
 }
 
-func NewQuotesRouter(service *services.QuoteService, mailService services.Mailer) *QuotesRouter {
+func NewQuotesRouter(service *services.QuoteService, mailService services.Mailer, shareTemplates *templates.ShareTemplates) *QuotesRouter {
 	return &QuotesRouter{
 		quotesService: service,
 		mailService: mailService,
+		shareTemplates: shareTemplates,
 	}
 }
 
@@ -38,9 +71,60 @@ func getHealth(w http.ResponseWriter, r *http.Request) {
 }
 
 func (qr *QuotesRouter) getRandomQuote(w http.ResponseWriter, r *http.Request) {
-	quote, err := qr.quotesService.GetRandomQuote()
+	filter, err := parseQuoteFilter(r)
 	if err != nil {
-		helpers.WriteJSONError(w, http.StatusNotFound, err.Error())
+		helpers.WriteJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var quote *models.Quote
+	if id := clientId(r); id != "" {
+		quote, err = qr.quotesService.GetNextQuoteForClient(id, filter)
+	} else {
+		// Anonymous callers get a client cookie so their next requests use a shuffle bag.
+		http.SetCookie(w, &http.Cookie{
+			Name:     clientCookieName,
+			Value:    uuid.New().String(),
+			Path:     "/",
+			MaxAge:   365 * 24 * 60 * 60,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		quote, err = qr.quotesService.GetRandomQuote(filter)
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
+
+func (qr *QuotesRouter) getQuoteOfTheDay(w http.ResponseWriter, r *http.Request) {
+	location := time.UTC
+	if tz := r.URL.Query().Get("tz"); tz != "" {
+		loaded, err := time.LoadLocation(tz)
+		if err != nil {
+			helpers.WriteJSONError(w, http.StatusBadRequest, fmt.Sprintf("Unknown time zone: %s", tz))
+			return
+		}
+		location = loaded
+	}
+
+	date := time.Now().In(location)
+	if value := r.URL.Query().Get("date"); value != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, value, location)
+		if err != nil {
+			helpers.WriteJSONError(w, http.StatusBadRequest, "date must be formatted as YYYY-MM-DD")
+			return
+		}
+		date = parsed
+	}
+
+	quote, err := qr.quotesService.GetQuoteOfTheDay(date)
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -68,11 +152,15 @@ func (qr *QuotesRouter) createQuote(w http.ResponseWriter, r *http.Request) {
 	}
 
 	text := strings.TrimSpace(quote.Text)
+	if text == "" {
+		helpers.WriteJSONError(w, http.StatusBadRequest, "Validation error: text must not be empty")
+		return
+	}
 	author := strings.TrimSpace(quote.Author)
 
-	newQuote, err := qr.quotesService.CreateQuote(text, author)
+	newQuote, err := qr.quotesService.CreateQuote(text, author, quote.Tags, creator(r))
 	if err != nil {
-		helpers.WriteJSONError(w, http.StatusInternalServerError, err.Error())
+		writeServiceError(w, err)
 		return
 	}
 
@@ -81,14 +169,257 @@ func (qr *QuotesRouter) createQuote(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(newQuote)
 }
 
+func (qr *QuotesRouter) getQuote(w http.ResponseWriter, r *http.Request) {
+	quote, err := qr.quotesService.GetQuote(r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
+
+func (qr *QuotesRouter) listQuotes(w http.ResponseWriter, r *http.Request) {
+	options, err := parseListOptions(r)
+	if err != nil {
+		helpers.WriteJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter, err := parseQuoteFilter(r)
+	if err != nil {
+		helpers.WriteJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := qr.quotesService.ListQuotes(filter, options)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// replaceQuote handles PUT: text is required and a missing author resets it to "Unknown".
+func (qr *QuotesRouter) replaceQuote(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+
+	quote := NewQuoteRequest{}
+	err := json.NewDecoder(r.Body).Decode(&quote)
+	if err != nil {
+		helpers.WriteJSONError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	validate := validator.New()
+
+	err = validate.Struct(quote)
+	if err != nil {
+		errors := err.(validator.ValidationErrors)
+		helpers.WriteJSONError(w, http.StatusBadRequest, fmt.Sprintf("Validation error: %s", errors))
+		return
+	}
+
+	text := strings.TrimSpace(quote.Text)
+	if text == "" {
+		helpers.WriteJSONError(w, http.StatusBadRequest, "Validation error: text must not be empty")
+		return
+	}
+	author := strings.TrimSpace(quote.Author)
+
+	updated, err := qr.quotesService.UpdateQuote(r.PathValue("id"), services.QuoteUpdate{Text: &text, Author: &author, Tags: &quote.Tags})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// patchQuote handles PATCH: only the fields present in the body are changed.
+func (qr *QuotesRouter) patchQuote(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+
+	quote := UpdateQuoteRequest{}
+	err := json.NewDecoder(r.Body).Decode(&quote)
+	if err != nil {
+		helpers.WriteJSONError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	validate := validator.New()
+
+	err = validate.Struct(quote)
+	if err != nil {
+		errors := err.(validator.ValidationErrors)
+		helpers.WriteJSONError(w, http.StatusBadRequest, fmt.Sprintf("Validation error: %s", errors))
+		return
+	}
+
+	update := services.QuoteUpdate{}
+	if quote.Text != nil {
+		text := strings.TrimSpace(*quote.Text)
+		if text == "" {
+			helpers.WriteJSONError(w, http.StatusBadRequest, "Validation error: text must not be empty")
+			return
+		}
+		update.Text = &text
+	}
+	if quote.Author != nil {
+		author := strings.TrimSpace(*quote.Author)
+		update.Author = &author
+	}
+	update.Tags = quote.Tags
+
+	updated, err := qr.quotesService.UpdateQuote(r.PathValue("id"), update)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (qr *QuotesRouter) deleteQuote(w http.ResponseWriter, r *http.Request) {
+	err := qr.quotesService.DeleteQuote(r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// exportQuotes streams the quotes matching the filters as a download:
+// ?format=json|ndjson|csv|yaml|fortune (default json), with gzip=true to compress it.
+func (qr *QuotesRouter) exportQuotes(w http.ResponseWriter, r *http.Request) {
+	format := formats.JSON
+	if value := r.URL.Query().Get("format"); value != "" {
+		parsed, err := formats.ParseFormat(value)
+		if err != nil || formats.ContentType(parsed) == "" {
+			helpers.WriteJSONError(w, http.StatusBadRequest, "format must be json, ndjson, csv, yaml or fortune")
+			return
+		}
+		format = parsed
+	}
+
+	compress, err := parseBoolParam(r, "gzip")
+	if err != nil {
+		helpers.WriteJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter, err := parseQuoteFilter(r)
+	if err != nil {
+		helpers.WriteJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filename := "quotes." + string(format)
+	var out io.Writer = w
+	if compress {
+		filename += ".gz"
+		w.Header().Set("Content-Type", "application/gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		out = gz
+	} else {
+		w.Header().Set("Content-Type", formats.ContentType(format))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	// Headers are sent with the first quote, so a failure part way can only be logged.
+	_, err = qr.quotesService.ExportQuotes(out, format, filter)
+	if err != nil {
+		log.Printf("Failed to export quotes: %s", err.Error())
+	}
+}
+
+// deleteQuotes purges every quote matching the filters. At least one of source,
+// batch_id or created_by is required so a bare DELETE /quotes cannot empty the store.
+func (qr *QuotesRouter) deleteQuotes(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseQuoteFilter(r)
+	if err != nil {
+		helpers.WriteJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Source == "" && filter.BatchId == "" && filter.CreatedBy == "" {
+		helpers.WriteJSONError(w, http.StatusBadRequest, "source, batch_id or created_by is required")
+		return
+	}
+
+	deleted, err := qr.quotesService.DeleteQuotes(filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"deleted": deleted})
+}
+
+func (qr *QuotesRouter) searchQuotes(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		helpers.WriteJSONError(w, http.StatusBadRequest, "q must not be empty")
+		return
+	}
+
+	limit := 20
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 100 {
+			helpers.WriteJSONError(w, http.StatusBadRequest, "limit must be an integer between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+
+	results, err := qr.quotesService.SearchQuotes(query, limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+func (qr *QuotesRouter) listTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := qr.quotesService.ListTags()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tags)
+}
+
+// emailRandomQuote mails a quote, random unless quote_id names one. The optional
+// sender_name and message personalize the email, and reply_to lets recipients
+// answer the sender instead of FROM_EMAIL.
 func (qr *QuotesRouter) emailRandomQuote(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+
 	var requestBody EmailRequest
 
 	err := json.NewDecoder(r.Body).Decode(&requestBody)
 	if err != nil {
 		helpers.WriteJSONError(w, http.StatusBadRequest, "Invalid JSON")
+		return
 	}
 
+	requestBody.QuoteId = strings.TrimSpace(requestBody.QuoteId)
+	requestBody.SenderName = singleLine(requestBody.SenderName)
+	requestBody.ReplyTo = strings.TrimSpace(requestBody.ReplyTo)
+	requestBody.Message = strings.TrimSpace(requestBody.Message)
+
 	validate := validator.New()
 
 	err = validate.Struct(requestBody)
@@ -98,17 +429,241 @@ func (qr *QuotesRouter) emailRandomQuote(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	quote, err := qr.quotesService.GetRandomQuote()
+	quote, err := qr.shareQuote(requestBody.QuoteId)
 	if err != nil {
-		helpers.WriteJSONError(w, http.StatusNotFound, err.Error())
+		writeServiceError(w, err)
+		return
+	}
+
+	rendered, err := qr.shareTemplates.Render(shareData(quote, requestBody.SenderName, requestBody.Message))
+	if err != nil {
+		helpers.WriteJSONError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to render email - %s", err.Error()))
 		return
 	}
 
-	body := fmt.Sprintf("\"%s\"\n\n - %s", quote.Text, quote.Author)
+	email := services.Email{
+		To:       requestBody.To,
+		FromName: relayedName(requestBody.SenderName),
+		ReplyTo:  requestBody.ReplyTo,
+		Subject:  rendered.Subject,
+		Text:     rendered.Text,
+		HTML:     rendered.HTML,
+	}
 
-	err = qr.mailService.SendMail(requestBody.To, "A motivating quote for you", body)
+	err = qr.mailService.SendMail(email)
 	if err != nil {
 		message := fmt.Sprintf("Failed to send email - %s", err.Error())
 		helpers.WriteJSONError(w, http.StatusInternalServerError, message)
 	}
 }
+
+// relayedName shows a sender name as passed on by motivate, so the recipient does
+// not take it for a message sent by that person directly.
+func relayedName(senderName string) string {
+	if senderName == "" {
+		return ""
+	}
+	return senderName + " via motivate"
+}
+
+// previewShare renders the share email without sending it: the HTML body by
+// default, the plain-text one with ?format=text, or subject and both bodies with
+// ?format=json. quote_id picks the quote (random otherwise); sender_name and
+// message fill in the personal parts.
+func (qr *QuotesRouter) previewShare(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := SharePreviewRequest{
+		QuoteId:    strings.TrimSpace(query.Get("quote_id")),
+		SenderName: singleLine(query.Get("sender_name")),
+		Message:    strings.TrimSpace(query.Get("message")),
+		Format:     query.Get("format"),
+	}
+
+	validate := validator.New()
+
+	err := validate.Struct(request)
+	if err != nil {
+		errors := err.(validator.ValidationErrors)
+		helpers.WriteJSONError(w, http.StatusBadRequest, fmt.Sprintf("Validation error: %s", errors))
+		return
+	}
+
+	quote, err := qr.shareQuote(request.QuoteId)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	rendered, err := qr.shareTemplates.Render(shareData(quote, request.SenderName, request.Message))
+	if err != nil {
+		helpers.WriteJSONError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to render email - %s", err.Error()))
+		return
+	}
+
+	switch request.Format {
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(rendered.Text))
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rendered)
+	default:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(rendered.HTML))
+	}
+}
+
+// shareQuote is the quote with the given id, or a random one if id is empty.
+func (qr *QuotesRouter) shareQuote(id string) (*models.Quote, error) {
+	if id != "" {
+		return qr.quotesService.GetQuote(id)
+	}
+	return qr.quotesService.GetRandomQuote(services.QuoteFilter{})
+}
+
+// singleLine collapses whitespace, including line breaks, so a name is safe in a header.
+func singleLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func shareData(quote *models.Quote, senderName, message string) templates.ShareData {
+	return templates.ShareData{
+		Quote:      quote.Text,
+		Author:     quote.Author,
+		Book:       quote.Book,
+		SenderName: senderName,
+		Message:    message,
+	}
+}
+
+func parseListOptions(r *http.Request) (services.ListOptions, error) {
+	query := r.URL.Query()
+	options := services.ListOptions{
+		Page:  1,
+		Limit: 20,
+		Sort:  services.SortByCreated,
+	}
+
+	if value := query.Get("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return options, errors.New("page must be a positive integer")
+		}
+		options.Page = page
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > 100 {
+			return options, errors.New("limit must be an integer between 1 and 100")
+		}
+		options.Limit = limit
+	}
+
+	switch sort := services.QuoteSort(query.Get("sort")); sort {
+	case "":
+	case services.SortByCreated, services.SortByAuthor:
+		options.Sort = sort
+	default:
+		return options, fmt.Errorf("sort must be %q or %q", services.SortByCreated, services.SortByAuthor)
+	}
+
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		options.Descending = true
+	default:
+		return options, errors.New("order must be \"asc\" or \"desc\"")
+	}
+
+	return options, nil
+}
+
+func parseBoolParam(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", name)
+	}
+	return parsed, nil
+}
+
+func parseQuoteFilter(r *http.Request) (services.QuoteFilter, error) {
+	query := r.URL.Query()
+	filter := services.QuoteFilter{
+		Author:    strings.TrimSpace(query.Get("author")),
+		Tag:       models.NormalizeTag(query.Get("tag")),
+		Language:  strings.TrimSpace(query.Get("lang")),
+		Source:    strings.TrimSpace(query.Get("source")),
+		BatchId:   strings.TrimSpace(query.Get("batch_id")),
+		CreatedBy: strings.TrimSpace(query.Get("created_by")),
+	}
+
+	for _, name := range []string{"min_length", "max_length"} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		length, err := strconv.Atoi(value)
+		if err != nil || length < 0 {
+			return filter, fmt.Errorf("%s must be a non-negative integer", name)
+		}
+		if name == "min_length" {
+			filter.MinLength = length
+		} else {
+			filter.MaxLength = length
+		}
+	}
+	if filter.MaxLength > 0 && filter.MinLength > filter.MaxLength {
+		return filter, errors.New("min_length must not be greater than max_length")
+	}
+
+	// exclude accepts repeated parameters and comma-separated lists: ?exclude=1,2&exclude=3
+	for _, value := range query["exclude"] {
+		for id := range strings.SplitSeq(value, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				filter.ExcludeIds = append(filter.ExcludeIds, id)
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+// clientId identifies the caller by X-Client-ID header, X-API-Key header or client cookie, in that order.
+func clientId(r *http.Request) string {
+	if id := strings.TrimSpace(r.Header.Get("X-Client-ID")); id != "" {
+		return "client:" + id
+	}
+	if key := strings.TrimSpace(r.Header.Get("X-API-Key")); key != "" {
+		return "key:" + key
+	}
+	if cookie, err := r.Cookie(clientCookieName); err == nil && cookie.Value != "" {
+		return "cookie:" + cookie.Value
+	}
+	return ""
+}
+
+// creator names the caller recorded on quotes it creates: its X-Client-ID, or
+// "anonymous". API keys and cookies are never stored.
+func creator(r *http.Request) string {
+	if id := strings.TrimSpace(r.Header.Get("X-Client-ID")); id != "" {
+		return id
+	}
+	return "anonymous"
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errs.ErrNotFound), errors.Is(err, errs.ErrEmpty), errors.Is(err, errs.ErrNoMatch):
+		helpers.WriteJSONError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, errs.ErrAlreadyExists), errors.Is(err, errs.ErrJobFinished):
+		helpers.WriteJSONError(w, http.StatusConflict, err.Error())
+	default:
+		helpers.WriteJSONError(w, http.StatusInternalServerError, err.Error())
+	}
+}
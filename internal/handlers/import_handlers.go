@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/danilobml/motivate/internal/formats"
+	"github.com/danilobml/motivate/internal/helpers"
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+const defaultImportMaxBytes = 32 << 20
+
+type ImportRouter struct {
+	jobService *services.ImportJobService
+}
+
+func NewImportRouter(jobService *services.ImportJobService) *ImportRouter {
+	return &ImportRouter{
+		jobService: jobService,
+	}
+}
+
+// importQuotes starts a background job storing the quotes of an uploaded file:
+// either the "file" part of a multipart form or the raw request body. The format
+// comes from the uploaded file name or its content, unless ?format= names it.
+// ?dry_run=true and ?strict=true behave like --seed-dry-run and --seed-strict.
+// The upload is read in full before answering 202 with the job to poll.
+func (ir *ImportRouter) importQuotes(w http.ResponseWriter, r *http.Request) {
+	options := services.SeedOptions{}
+
+	if value := r.URL.Query().Get("format"); value != "" {
+		format, err := formats.ParseFormat(value)
+		if err != nil {
+			helpers.WriteJSONError(w, http.StatusBadRequest, "format must be json, ndjson, csv, yaml, fortune, kindle or goodreads")
+			return
+		}
+		options.Format = format
+	}
+
+	var err error
+	options.DryRun, err = parseBoolParam(r, "dry_run")
+	if err != nil {
+		helpers.WriteJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	options.Strict, err = parseBoolParam(r, "strict")
+	if err != nil {
+		helpers.WriteJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, int64(helpers.GetenvInt("IMPORT_MAX_BYTES", defaultImportMaxBytes)))
+
+	path, name, err := spoolUpload(r)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		helpers.WriteJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("upload is larger than %d bytes", tooLarge.Limit))
+		return
+	}
+	if err != nil {
+		helpers.WriteJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job := ir.jobService.Start(path, name, models.SourceUpload, options)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/jobs/"+job.Id)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+func (ir *ImportRouter) getJob(w http.ResponseWriter, r *http.Request) {
+	job, err := ir.jobService.Get(r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// cancelJob stops a queued or running job; poll it until its state is "cancelled".
+func (ir *ImportRouter) cancelJob(w http.ResponseWriter, r *http.Request) {
+	job, err := ir.jobService.Cancel(r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// spoolUpload copies the uploaded file to a temporary file and returns its path
+// and the uploaded name. The temporary name ends with the uploaded one, so
+// "My Clippings.txt" is still recognised as Kindle clippings.
+func spoolUpload(r *http.Request) (string, string, error) {
+	var body io.Reader = r.Body
+	name := "upload"
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		part, err := findFilePart(r)
+		if err != nil {
+			return "", "", err
+		}
+		defer part.Close()
+
+		body = part
+		if base := filepath.Base(part.FileName()); base != "." && base != string(filepath.Separator) {
+			name = base
+		}
+	}
+
+	file, err := os.CreateTemp("", "motivate-import-*-"+name)
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, body); err != nil {
+		os.Remove(file.Name())
+		return "", "", err
+	}
+
+	return file.Name(), name, nil
+}
+
+func findFilePart(r *http.Request) (*multipart.Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New(`multipart upload has no "file" part`)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
@@ -2,17 +2,44 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/danilobml/motivate/internal/helpers"
 	"github.com/danilobml/motivate/internal/httpx/middleware"
 )
 
-func RegisterRoutes(qr *QuotesRouter) http.Handler {
+const defaultShareRateLimit = 10
+
+func RegisterRoutes(qr *QuotesRouter, ir *ImportRouter, ar *AdminRouter) http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("GET /health", getHealth)
 	mux.HandleFunc("GET /quote", qr.getRandomQuote)
+	mux.HandleFunc("GET /quote/today", qr.getQuoteOfTheDay)
 	mux.HandleFunc("POST /add", qr.createQuote)
-	mux.HandleFunc("POST /share", qr.emailRandomQuote)
+	mux.Handle("POST /share", middleware.RateLimit(helpers.GetenvInt("SHARE_RATE_LIMIT", defaultShareRateLimit), time.Hour, http.HandlerFunc(qr.emailRandomQuote)))
+	mux.HandleFunc("GET /share/preview", qr.previewShare)
+
+	mux.HandleFunc("GET /quotes", qr.listQuotes)
+	mux.HandleFunc("DELETE /quotes", qr.deleteQuotes)
+	mux.HandleFunc("GET /quotes/search", qr.searchQuotes)
+	mux.HandleFunc("GET /quotes/export", qr.exportQuotes)
+	mux.HandleFunc("GET /quotes/{id}", qr.getQuote)
+	mux.HandleFunc("PUT /quotes/{id}", qr.replaceQuote)
+	mux.HandleFunc("PATCH /quotes/{id}", qr.patchQuote)
+	mux.HandleFunc("DELETE /quotes/{id}", qr.deleteQuote)
+
+	mux.HandleFunc("GET /tags", qr.listTags)
+
+	if ir != nil {
+		mux.HandleFunc("POST /quotes/import", ir.importQuotes)
+		mux.HandleFunc("GET /jobs/{id}", ir.getJob)
+		mux.HandleFunc("DELETE /jobs/{id}", ir.cancelJob)
+	}
+
+	if ar != nil {
+		mux.HandleFunc("GET /admin/sync", ar.getSyncStatus)
+	}
 
 	return middleware.Cors(middleware.RequestId(middleware.Logger(middleware.Recover(mux))))
 }
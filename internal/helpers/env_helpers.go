@@ -28,3 +28,16 @@ func GetenvString(key string, defaultValue string) string {
 	
 	return value
 }
+
+func GetenvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid value for %s, using default %d", key, defaultValue)
+		return defaultValue
+	}
+	return number
+}
@@ -21,8 +21,8 @@ func Cors(mux http.Handler) http.Handler {
 		
 		c := cors.New(cors.Options{
 			AllowedOrigins:   allowedOrigins,
-			AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
-			AllowedHeaders:   []string{"Content-Type", "Authorization"},
+			AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Client-ID", "X-API-Key"},
 			AllowCredentials: false,
 		})
 		c.Handler(mux).ServeHTTP(w, r)
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/danilobml/motivate/internal/helpers"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimit lets each client IP make bursts of up to requests calls to next and,
+// on average, requests per interval; the rest get 429 with Retry-After.
+// A requests of 0 or less disables the limit.
+func RateLimit(requests int, per time.Duration, next http.Handler) http.Handler {
+	if requests <= 0 || per <= 0 {
+		return next
+	}
+
+	var (
+		mu        sync.Mutex
+		buckets   = map[string]*bucket{}
+		lastSweep = time.Now()
+		capacity  = float64(requests)
+		perToken  = per / time.Duration(requests)
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		mu.Lock()
+		now := time.Now()
+		// Buckets that have refilled hold no state worth keeping.
+		if now.Sub(lastSweep) >= per {
+			for key, b := range buckets {
+				if now.Sub(b.last) >= per {
+					delete(buckets, key)
+				}
+			}
+			lastSweep = now
+		}
+
+		b, ok := buckets[ip]
+		if !ok {
+			b = &bucket{tokens: capacity, last: now}
+			buckets[ip] = b
+		}
+		b.tokens = min(capacity, b.tokens+float64(now.Sub(b.last))/float64(perToken))
+		b.last = now
+
+		allowed := b.tokens >= 1
+		if allowed {
+			b.tokens--
+		}
+		wait := time.Duration((1 - b.tokens) * float64(perToken))
+		mu.Unlock()
+
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			helpers.WriteJSONError(w, http.StatusTooManyRequests, "Too many requests, try again later")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
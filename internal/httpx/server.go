@@ -12,7 +12,9 @@ import (
 	"github.com/danilobml/motivate/internal/helpers"
 )
 
-func NewServer(handler http.Handler) {
+// NewServer serves handler until SIGINT/SIGTERM, then shuts down and runs
+// onShutdown in order, e.g. to stop background work before storage is closed.
+func NewServer(handler http.Handler, onShutdown ...func()) {
 	srv := http.Server{
 		Addr:              helpers.GetenvString("PORT", ":8080"),
 		ReadHeaderTimeout: helpers.GetenvDuration("READ_HEADER_TIMEOUT", 5),
@@ -30,10 +32,10 @@ func NewServer(handler http.Handler) {
 		}
 	}()
 
-	waitForShutdown(&srv, 5*time.Second)
+	waitForShutdown(&srv, 5*time.Second, onShutdown)
 }
 
-func waitForShutdown(srv *http.Server, timeout time.Duration) {
+func waitForShutdown(srv *http.Server, timeout time.Duration, onShutdown []func()) {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
@@ -48,5 +50,9 @@ func waitForShutdown(srv *http.Server, timeout time.Duration) {
 		_ = srv.Close()
 	}
 
+	for _, hook := range onShutdown {
+		hook()
+	}
+
 	log.Println("Shutdown complete.")
 }
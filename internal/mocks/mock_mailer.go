@@ -1,14 +1,27 @@
 package mocks
 
+import "github.com/danilobml/motivate/internal/services"
+
+// MockMailer records the last email instead of sending it. Message is the
+// email as MailService would send it from MockSender.
 type MockMailer struct {
 	To      []string
 	Subject string
 	Message string
+	Email   services.Email
 }
 
-func (m *MockMailer) SendMail(to []string, subject, body string) error {
-	m.To = append([]string(nil), to...)
-	m.Subject = subject
-	m.Message = "Subject: " + subject + "\n" + body
+const MockSender = "motivate@example.com"
+
+func (m *MockMailer) SendMail(email services.Email) error {
+	message, err := services.ComposeMessage(MockSender, email)
+	if err != nil {
+		return err
+	}
+
+	m.To = append([]string(nil), email.To...)
+	m.Subject = email.Subject
+	m.Message = string(message)
+	m.Email = email
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,11 @@
+package models
+
+type DummyJsonQuote struct {
+	Id     int    `json:"id"`
+	Quote  string `json:"quote"`
+	Author string `json:"author"`
+}
+
+type DummyJsonQuotesResponse struct {
+	Quotes []DummyJsonQuote `json:"quotes"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type QuotableQuote struct {
+	Id      string   `json:"_id"`
+	Content string   `json:"content"`
+	Author  string   `json:"author"`
+	Tags    []string `json:"tags"`
+}
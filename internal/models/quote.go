@@ -1,7 +1,35 @@
 package models
 
+import "time"
+
+// Sources recorded on quotes that do not come from an external API, which use the API's name.
+const (
+	SourceApi       = "api"
+	SourceFile      = "file"
+	SourceUpload    = "upload"
+	SourceKindle    = "kindle"
+	SourceGoodreads = "goodreads"
+)
+
 type Quote struct {
-	Id     string `json:"id"`
-	Text   string `json:"text"`
-	Author string `json:"author"`
+	Id       string   `json:"id"`
+	Text     string   `json:"text"`
+	Author   string   `json:"author"`
+	Tags     []string `json:"tags,omitempty"`
+	Language string   `json:"language,omitempty"`
+	Book     string   `json:"book,omitempty"`
+
+	// Provenance: where the quote came from, its id there (for Kindle highlights,
+	// the location in Book), the import run that stored it and who created it.
+	Source     string    `json:"source,omitempty"`
+	ExternalId string    `json:"external_id,omitempty"`
+	BatchId    string    `json:"batch_id,omitempty"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitzero"`
+	UpdatedAt  time.Time `json:"updated_at,omitzero"`
+}
+
+type SearchResult struct {
+	Quote Quote   `json:"quote"`
+	Score float64 `json:"score"`
 }
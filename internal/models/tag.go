@@ -0,0 +1,29 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// NormalizeTag lowercases a tag and joins its words with dashes: " Self  Improvement" -> "self-improvement".
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+// NormalizeTags normalizes every tag, drops empty ones and duplicates, and sorts the result.
+func NormalizeTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	slices.Sort(normalized)
+
+	return slices.Compact(normalized)
+}
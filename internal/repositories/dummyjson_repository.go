@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+type DummyJsonRepository struct {
+	BaseUrl string
+	Client  *http.Client
+}
+
+// NewDummyJsonRepository uses client for requests, or NewSourceHTTPClient() if it is nil.
+func NewDummyJsonRepository(baseUrl string, client *http.Client) *DummyJsonRepository {
+	if client == nil {
+		client = NewSourceHTTPClient()
+	}
+
+	return &DummyJsonRepository{
+		BaseUrl: baseUrl,
+		Client:  client,
+	}
+}
+
+func (dr *DummyJsonRepository) Name() string {
+	return "dummyjson"
+}
+
+func (dr *DummyJsonRepository) Fetch(ctx context.Context) ([]models.Quote, error) {
+	var response models.DummyJsonQuotesResponse
+	err := fetchJSON(ctx, dr.Client, dr.Name(), dr.BaseUrl, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := []models.Quote{}
+	for _, dummyQuote := range response.Quotes {
+		quotes = append(quotes, models.Quote{
+			Text:       dummyQuote.Quote,
+			Author:     dummyQuote.Author,
+			ExternalId: strconv.Itoa(dummyQuote.Id),
+		})
+	}
+
+	return quotes, nil
+}
@@ -0,0 +1,331 @@
+package repositories
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/models"
+)
+
+const defaultCompactEvery = 1000
+
+type journalEntry struct {
+	Op    string        `json:"op"`
+	Id    string        `json:"id,omitempty"`
+	Quote *models.Quote `json:"quote,omitempty"`
+}
+
+// FileQuoteRepository keeps quotes in memory and makes them durable with two files:
+// a snapshot (a JSON array of quotes, same shape as seed_quotes.json) and an
+// append-only journal of every Save/Delete since that snapshot. On startup the
+// snapshot is loaded and the journal replayed; every compactEvery entries the
+// journal is folded into a new snapshot written atomically (temp file + rename).
+type FileQuoteRepository struct {
+	mu           sync.Mutex
+	memory       *InMemoryQuoteRepository
+	snapshotPath string
+	journalPath  string
+	journal      *os.File
+	entries      int
+	compactEvery int
+}
+
+func NewFileQuoteRepository(snapshotPath string, compactEvery int) (*FileQuoteRepository, error) {
+	if compactEvery <= 0 {
+		compactEvery = defaultCompactEvery
+	}
+
+	fr := &FileQuoteRepository{
+		memory:       NewInMemoryQuoteRepository(),
+		snapshotPath: snapshotPath,
+		journalPath:  snapshotPath + ".log",
+		compactEvery: compactEvery,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(snapshotPath), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	if err := fr.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := fr.replayJournal(); err != nil {
+		return nil, err
+	}
+
+	journal, err := os.OpenFile(fr.journalPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	fr.journal = journal
+
+	// Fold whatever was replayed into a fresh snapshot so a partial line left by
+	// a crash is dropped before new entries are appended after it.
+	if err := fr.compact(); err != nil {
+		journal.Close()
+		return nil, err
+	}
+
+	return fr, nil
+}
+
+// OpenFileQuoteRepositoryReadOnly loads the snapshot and replays the journal
+// without opening the journal for writing or compacting, so the files on disk
+// are left untouched. Save, Delete and Compact return errs.ErrClosed.
+func OpenFileQuoteRepositoryReadOnly(snapshotPath string) (*FileQuoteRepository, error) {
+	fr := &FileQuoteRepository{
+		memory:       NewInMemoryQuoteRepository(),
+		snapshotPath: snapshotPath,
+		journalPath:  snapshotPath + ".log",
+		compactEvery: defaultCompactEvery,
+	}
+
+	// Every store has a snapshot once opened for writing, so a missing one is a wrong path.
+	if _, err := os.Stat(snapshotPath); err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	if err := fr.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := fr.replayJournal(); err != nil {
+		return nil, err
+	}
+
+	return fr, nil
+}
+
+func (fr *FileQuoteRepository) loadSnapshot() error {
+	file, err := os.Open(fr.snapshotPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer file.Close()
+
+	quotes := []models.Quote{}
+	err = json.NewDecoder(file).Decode(&quotes)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	for _, quote := range quotes {
+		fr.memory.Save(quote)
+	}
+
+	return nil
+}
+
+func (fr *FileQuoteRepository) replayJournal() error {
+	file, err := os.Open(fr.journalPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	for scanner.Scan() {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// A crash mid-append leaves a partial last line; everything before it is intact.
+			break
+		}
+		fr.apply(entry)
+		fr.entries++
+	}
+
+	return scanner.Err()
+}
+
+func (fr *FileQuoteRepository) apply(entry journalEntry) {
+	switch entry.Op {
+	case "save":
+		if entry.Quote != nil {
+			fr.memory.Save(*entry.Quote)
+		}
+	case "delete":
+		fr.memory.Delete(entry.Id)
+	}
+}
+
+func (fr *FileQuoteRepository) List() ([]models.Quote, error) {
+	return fr.memory.List()
+}
+
+func (fr *FileQuoteRepository) Find(id string) (*models.Quote, error) {
+	return fr.memory.Find(id)
+}
+
+func (fr *FileQuoteRepository) ListTags() ([]models.TagCount, error) {
+	return fr.memory.ListTags()
+}
+
+func (fr *FileQuoteRepository) Search(query string, limit int) ([]models.SearchResult, error) {
+	return fr.memory.Search(query, limit)
+}
+
+func (fr *FileQuoteRepository) Save(quote models.Quote) (*models.Quote, error) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	err := fr.append(journalEntry{Op: "save", Quote: &quote})
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := fr.memory.Save(quote)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fr.compactIfDue(); err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+func (fr *FileQuoteRepository) Delete(id string) error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	_, err := fr.memory.Find(id)
+	if err != nil {
+		return err
+	}
+
+	err = fr.append(journalEntry{Op: "delete", Id: id})
+	if err != nil {
+		return err
+	}
+
+	err = fr.memory.Delete(id)
+	if err != nil {
+		return err
+	}
+
+	return fr.compactIfDue()
+}
+
+func (fr *FileQuoteRepository) append(entry journalEntry) error {
+	if fr.journal == nil {
+		return errs.ErrClosed
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = fr.journal.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := fr.journal.Sync(); err != nil {
+		return fmt.Errorf("failed to sync journal: %w", err)
+	}
+
+	fr.entries++
+
+	return nil
+}
+
+// compactIfDue folds the journal into a snapshot once it holds compactEvery
+// entries. It runs after the entry has been applied to memory, so the snapshot
+// includes it before the journal is truncated.
+func (fr *FileQuoteRepository) compactIfDue() error {
+	if fr.entries < fr.compactEvery {
+		return nil
+	}
+
+	return fr.compact()
+}
+
+// Compact writes a fresh snapshot and truncates the journal.
+func (fr *FileQuoteRepository) Compact() error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	if fr.journal == nil {
+		return errs.ErrClosed
+	}
+
+	return fr.compact()
+}
+
+func (fr *FileQuoteRepository) compact() error {
+	quotes, err := fr.memory.List()
+	if err != nil {
+		return err
+	}
+
+	err = writeFileAtomic(fr.snapshotPath, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(quotes)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	// The snapshot now covers every journaled entry. Replaying a journal that
+	// survived a crash right here is harmless: saves and deletes are idempotent.
+	if err := fr.journal.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate journal: %w", err)
+	}
+	fr.entries = 0
+
+	return nil
+}
+
+func (fr *FileQuoteRepository) Close() error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	if fr.journal == nil {
+		return nil
+	}
+
+	err := fr.compact()
+	closeErr := fr.journal.Close()
+	fr.journal = nil
+
+	return errors.Join(err, closeErr)
+}
+
+func writeFileAtomic(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	err = write(writer)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/helpers"
+)
+
+// maxSourceBodyBytes caps how much of an upstream response is read.
+const maxSourceBodyBytes = 5 << 20
+
+// SourceError describes a failed request to an external quote source. It unwraps to
+// errs.ErrRateLimited, errs.ErrNetwork or errs.ErrBadUpstreamResponse.
+type SourceError struct {
+	Source     string
+	StatusCode int
+	// RetryAfter is the wait the upstream asked for via Retry-After, if any.
+	RetryAfter time.Duration
+	Err        error
+	Cause      error
+}
+
+func (se *SourceError) Error() string {
+	message := fmt.Sprintf("%s: %s", se.Source, se.Err)
+	if se.StatusCode != 0 {
+		message += fmt.Sprintf(" (status %d)", se.StatusCode)
+	}
+	if se.Cause != nil {
+		message += ": " + se.Cause.Error()
+	}
+	return message
+}
+
+func (se *SourceError) Unwrap() []error {
+	if se.Cause == nil {
+		return []error{se.Err}
+	}
+	return []error{se.Err, se.Cause}
+}
+
+// NewSourceHTTPClient returns the client used by quote sources when none is injected.
+func NewSourceHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: helpers.GetenvDuration("SOURCE_HTTP_TIMEOUT", 10),
+	}
+}
+
+// fetchJSON GETs url and decodes its JSON body into v, translating failures into *SourceError.
+func fetchJSON(ctx context.Context, client *http.Client, source, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &SourceError{Source: source, Err: errs.ErrNetwork, Cause: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &SourceError{
+			Source:     source,
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			Err:        errs.ErrRateLimited,
+		}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &SourceError{
+			Source:     source,
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			Err:        errs.ErrBadUpstreamResponse,
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return &SourceError{
+			Source:     source,
+			StatusCode: resp.StatusCode,
+			Err:        errs.ErrBadUpstreamResponse,
+			Cause:      fmt.Errorf("content type %q is not JSON", resp.Header.Get("Content-Type")),
+		}
+	}
+
+	body := http.MaxBytesReader(nil, resp.Body, maxSourceBodyBytes)
+	err = json.NewDecoder(body).Decode(v)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			err = fmt.Errorf("body larger than %d bytes", maxSourceBodyBytes)
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &SourceError{Source: source, StatusCode: resp.StatusCode, Err: errs.ErrBadUpstreamResponse, Cause: err}
+	}
+
+	return nil
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+type QuotableRepository struct {
+	BaseUrl string
+	Client  *http.Client
+}
+
+// NewQuotableRepository uses client for requests, or NewSourceHTTPClient() if it is nil.
+func NewQuotableRepository(baseUrl string, client *http.Client) *QuotableRepository {
+	if client == nil {
+		client = NewSourceHTTPClient()
+	}
+
+	return &QuotableRepository{
+		BaseUrl: baseUrl,
+		Client:  client,
+	}
+}
+
+func (qr *QuotableRepository) Name() string {
+	return "quotable"
+}
+
+func (qr *QuotableRepository) Fetch(ctx context.Context) ([]models.Quote, error) {
+	var quotableQuotes []models.QuotableQuote
+	err := fetchJSON(ctx, qr.Client, qr.Name(), qr.BaseUrl, &quotableQuotes)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := []models.Quote{}
+	for _, quotableQuote := range quotableQuotes {
+		quotes = append(quotes, models.Quote{
+			Text:       quotableQuote.Content,
+			Author:     quotableQuote.Author,
+			Tags:       quotableQuote.Tags,
+			Language:   "en",
+			ExternalId: quotableQuote.Id,
+		})
+	}
+
+	return quotes, nil
+}
@@ -1,62 +1,142 @@
 package repositories
 
 import (
-	"errors"
 	"slices"
+	"strings"
+	"sync"
 
 	"github.com/danilobml/motivate/internal/errs"
 	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/search"
 )
 
 type InMemoryQuoteRepository struct {
-	data []models.Quote
+	mu     sync.RWMutex
+	data   []models.Quote
+	index  map[string]int
+	search *search.Index
 }
 
 func NewInMemoryQuoteRepository() *InMemoryQuoteRepository {
 	return &InMemoryQuoteRepository{
-		data: []models.Quote{},
+		data:   []models.Quote{},
+		index:  map[string]int{},
+		search: search.NewIndex(),
 	}
 }
 
-func (ir *InMemoryQuoteRepository) List() []models.Quote {
-	return ir.data
+// List returns a snapshot of the stored quotes. Callers may modify it freely.
+func (ir *InMemoryQuoteRepository) List() ([]models.Quote, error) {
+	ir.mu.RLock()
+	defer ir.mu.RUnlock()
+
+	quotes := make([]models.Quote, len(ir.data))
+	for i, quote := range ir.data {
+		quotes[i] = cloneQuote(quote)
+	}
+
+	return quotes, nil
 }
 
 func (ir *InMemoryQuoteRepository) Find(id string) (*models.Quote, error) {
-	for i := range ir.data {
-		if ir.data[i].Id == id {
-			return &ir.data[i], nil
-		}
+	ir.mu.RLock()
+	defer ir.mu.RUnlock()
+
+	i, ok := ir.index[id]
+	if !ok {
+		return nil, errs.ErrNotFound
 	}
 
-	return nil, errs.ErrNotFound
+	quote := cloneQuote(ir.data[i])
+	return &quote, nil
 }
 
 func (ir *InMemoryQuoteRepository) Save(quote models.Quote) (*models.Quote, error) {
-	dbQuote, err := ir.Find(quote.Id)
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	stored := cloneQuote(quote)
+	ir.search.Add(quote.Id, quote.Text, quote.Author)
 
-	switch {
-	case err == nil:
-		dbQuote.Author = quote.Author
-		dbQuote.Text = quote.Text
-		return dbQuote, nil
-	case errors.Is(err, errs.ErrNotFound):
-		ir.data = append(ir.data, quote)
-		return &ir.data[len(ir.data)-1], nil
-	default:
-		return nil, err
+	if i, ok := ir.index[quote.Id]; ok {
+		ir.data[i] = stored
+		return &quote, nil
 	}
+
+	ir.index[quote.Id] = len(ir.data)
+	ir.data = append(ir.data, stored)
+
+	return &quote, nil
 }
 
 func (ir *InMemoryQuoteRepository) Delete(id string) error {
-	_, err := ir.Find(id)
-	if err != nil {
-		return err
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	i, ok := ir.index[id]
+	if !ok {
+		return errs.ErrNotFound
 	}
 
-	ir.data = slices.DeleteFunc(ir.data, func(quote models.Quote) bool {
-		return quote.Id == id
-	})
+	ir.data = slices.Delete(ir.data, i, i+1)
+	delete(ir.index, id)
+	ir.search.Remove(id)
+	for j := i; j < len(ir.data); j++ {
+		ir.index[ir.data[j].Id] = j
+	}
 
 	return nil
 }
+
+// Search ranks quotes by how well their text and author match query.
+func (ir *InMemoryQuoteRepository) Search(query string, limit int) ([]models.SearchResult, error) {
+	ir.mu.RLock()
+	defer ir.mu.RUnlock()
+
+	results := []models.SearchResult{}
+	for _, hit := range ir.search.Search(query, limit) {
+		results = append(results, models.SearchResult{
+			Quote: cloneQuote(ir.data[ir.index[hit.Id]]),
+			Score: hit.Score,
+		})
+	}
+
+	return results, nil
+}
+
+// ListTags counts the quotes carrying each tag, most used first.
+func (ir *InMemoryQuoteRepository) ListTags() ([]models.TagCount, error) {
+	ir.mu.RLock()
+	defer ir.mu.RUnlock()
+
+	counts := map[string]int{}
+	for _, quote := range ir.data {
+		for _, tag := range quote.Tags {
+			counts[tag]++
+		}
+	}
+
+	return sortTagCounts(counts), nil
+}
+
+func sortTagCounts(counts map[string]int) []models.TagCount {
+	tags := []models.TagCount{}
+	for tag, count := range counts {
+		tags = append(tags, models.TagCount{Tag: tag, Count: count})
+	}
+
+	slices.SortFunc(tags, func(a, b models.TagCount) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Tag, b.Tag)
+	})
+
+	return tags
+}
+
+// cloneQuote copies the slices inside a quote so callers never share memory with the store.
+func cloneQuote(quote models.Quote) models.Quote {
+	quote.Tags = slices.Clone(quote.Tags)
+	return quote
+}
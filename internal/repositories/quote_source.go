@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+// QuoteSource is an external provider of quotes. Fetched quotes have no Id;
+// callers assign one when storing them.
+type QuoteSource interface {
+	Name() string
+	Fetch(ctx context.Context) ([]models.Quote, error)
+}
+
+// Client-side limits matching each provider's documented quota, shared by every
+// source built for that provider.
+var (
+	zenQuotesLimiter = NewRateLimiter(5, 30*time.Second)
+	quotableLimiter  = NewRateLimiter(180, time.Minute)
+)
+
+var quoteSources = map[string]func() QuoteSource{
+	"zenquotes": func() QuoteSource {
+		return NewRetryingSource(NewZenQuoteRepository("https://zenquotes.io/api/quotes", nil), DefaultRetryPolicy(), zenQuotesLimiter)
+	},
+	"quotable": func() QuoteSource {
+		return NewRetryingSource(NewQuotableRepository("https://api.quotable.io/quotes/random?limit=50", nil), DefaultRetryPolicy(), quotableLimiter)
+	},
+	"dummyjson": func() QuoteSource {
+		return NewRetryingSource(NewDummyJsonRepository("https://dummyjson.com/quotes?limit=0", nil), DefaultRetryPolicy(), nil)
+	},
+}
+
+// NewQuoteSourceByName builds one of the known sources with its public API url,
+// retrying transient failures and respecting the provider's request quota.
+func NewQuoteSourceByName(name string) (QuoteSource, error) {
+	newSource, ok := quoteSources[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("unknown quote source %q, expected one of: %s", name, strings.Join(QuoteSourceNames(), ", "))
+	}
+
+	return newSource(), nil
+}
+
+func QuoteSourceNames() []string {
+	names := []string{}
+	for name := range quoteSources {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import "github.com/danilobml/motivate/internal/models"
+
+type QuoteRepository interface {
+	List() ([]models.Quote, error)
+	Find(id string) (*models.Quote, error)
+	Save(quote models.Quote) (*models.Quote, error)
+	Delete(id string) error
+	ListTags() ([]models.TagCount, error)
+	Search(query string, limit int) ([]models.SearchResult, error)
+}
@@ -0,0 +1,266 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+)
+
+// RunConformance runs the behaviour every QuoteRepository implementation must share.
+// newRepo is called once per subtest and must return an empty repository.
+func RunConformance(t *testing.T, newRepo func(t *testing.T) repositories.QuoteRepository) {
+	t.Run("List_Empty", func(t *testing.T) {
+		repo := newRepo(t)
+
+		quotes, err := repo.List()
+		require.NoError(t, err)
+		require.Empty(t, quotes)
+	})
+
+	t.Run("Save_Then_Find", func(t *testing.T) {
+		repo := newRepo(t)
+
+		saved, err := repo.Save(models.Quote{Id: "1", Text: "Test Text", Author: "Test Author"})
+		require.NoError(t, err)
+		require.Equal(t, "1", saved.Id)
+		require.Equal(t, "Test Text", saved.Text)
+		require.Equal(t, "Test Author", saved.Author)
+
+		found, err := repo.Find("1")
+		require.NoError(t, err)
+		require.Equal(t, saved.Text, found.Text)
+		require.Equal(t, saved.Author, found.Author)
+	})
+
+	t.Run("Save_Existing_Updates", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.Save(models.Quote{Id: "1", Text: "Old Text", Author: "Old Author"})
+		require.NoError(t, err)
+
+		updated, err := repo.Save(models.Quote{Id: "1", Text: "New Text", Author: "New Author"})
+		require.NoError(t, err)
+		require.Equal(t, "New Text", updated.Text)
+		require.Equal(t, "New Author", updated.Author)
+
+		quotes, err := repo.List()
+		require.NoError(t, err)
+		require.Len(t, quotes, 1)
+		require.Equal(t, "New Text", quotes[0].Text)
+	})
+
+	t.Run("Find_Missing_ErrNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.Find("missing")
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("List_Returns_All", func(t *testing.T) {
+		repo := newRepo(t)
+
+		for _, id := range []string{"1", "2", "3"} {
+			_, err := repo.Save(models.Quote{Id: id, Text: "Text " + id, Author: "Author"})
+			require.NoError(t, err)
+		}
+
+		quotes, err := repo.List()
+		require.NoError(t, err)
+		require.Len(t, quotes, 3)
+
+		ids := []string{}
+		for _, quote := range quotes {
+			ids = append(ids, quote.Id)
+		}
+		require.ElementsMatch(t, []string{"1", "2", "3"}, ids)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.Save(models.Quote{Id: "1", Text: "Text", Author: "Author"})
+		require.NoError(t, err)
+		_, err = repo.Save(models.Quote{Id: "2", Text: "Text", Author: "Author"})
+		require.NoError(t, err)
+
+		err = repo.Delete("1")
+		require.NoError(t, err)
+
+		_, err = repo.Find("1")
+		require.ErrorIs(t, err, errs.ErrNotFound)
+
+		quotes, err := repo.List()
+		require.NoError(t, err)
+		require.Len(t, quotes, 1)
+		require.Equal(t, "2", quotes[0].Id)
+	})
+
+	t.Run("Delete_Missing_ErrNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		err := repo.Delete("missing")
+		require.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("Returned_Quotes_Are_Copies", func(t *testing.T) {
+		repo := newRepo(t)
+
+		saved, err := repo.Save(models.Quote{Id: "1", Text: "Text", Author: "Author"})
+		require.NoError(t, err)
+		saved.Text = "Changed by Save caller"
+
+		found, err := repo.Find("1")
+		require.NoError(t, err)
+		found.Text = "Changed by Find caller"
+
+		quotes, err := repo.List()
+		require.NoError(t, err)
+		quotes[0].Text = "Changed by List caller"
+
+		found, err = repo.Find("1")
+		require.NoError(t, err)
+		require.Equal(t, "Text", found.Text)
+	})
+
+	t.Run("Provenance_RoundTrip", func(t *testing.T) {
+		repo := newRepo(t)
+		created := time.Date(2024, 3, 1, 9, 30, 0, 123456789, time.UTC)
+
+		quote := models.Quote{
+			Id: "1", Text: "Text", Author: "Author", Book: "Book",
+			Source: "zenquotes", ExternalId: "ext-1", BatchId: "batch-1", CreatedBy: "system",
+			CreatedAt: created, UpdatedAt: created,
+		}
+		_, err := repo.Save(quote)
+		require.NoError(t, err)
+
+		found, err := repo.Find("1")
+		require.NoError(t, err)
+		require.Equal(t, quote, *found)
+
+		quote.UpdatedAt = created.Add(time.Hour)
+		_, err = repo.Save(quote)
+		require.NoError(t, err)
+
+		quotes, err := repo.List()
+		require.NoError(t, err)
+		require.Equal(t, []models.Quote{quote}, quotes)
+
+		_, err = repo.Save(models.Quote{Id: "2", Text: "Text", Author: "Author"})
+		require.NoError(t, err)
+		found, err = repo.Find("2")
+		require.NoError(t, err)
+		require.True(t, found.CreatedAt.IsZero())
+		require.Empty(t, found.Source)
+	})
+
+	t.Run("Tags_And_Language_RoundTrip", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.Save(models.Quote{Id: "1", Text: "Text", Author: "Author", Tags: []string{"wisdom", "life"}, Language: "en"})
+		require.NoError(t, err)
+		_, err = repo.Save(models.Quote{Id: "2", Text: "Text", Author: "Author", Tags: []string{"life"}})
+		require.NoError(t, err)
+
+		found, err := repo.Find("1")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"wisdom", "life"}, found.Tags)
+		require.Equal(t, "en", found.Language)
+
+		_, err = repo.Save(models.Quote{Id: "1", Text: "Text", Author: "Author", Tags: []string{"courage"}, Language: "de"})
+		require.NoError(t, err)
+
+		quotes, err := repo.List()
+		require.NoError(t, err)
+		require.Len(t, quotes, 2)
+		require.Equal(t, []string{"courage"}, quotes[0].Tags)
+		require.Equal(t, "de", quotes[0].Language)
+		require.Equal(t, []string{"life"}, quotes[1].Tags)
+		require.Empty(t, quotes[1].Language)
+	})
+
+	t.Run("Returned_Tags_Are_Copies", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.Save(models.Quote{Id: "1", Text: "Text", Author: "Author", Tags: []string{"life"}})
+		require.NoError(t, err)
+
+		found, err := repo.Find("1")
+		require.NoError(t, err)
+		found.Tags[0] = "changed"
+
+		found, err = repo.Find("1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"life"}, found.Tags)
+	})
+
+	t.Run("ListTags_Counts_Quotes_Per_Tag", func(t *testing.T) {
+		repo := newRepo(t)
+
+		tags, err := repo.ListTags()
+		require.NoError(t, err)
+		require.Empty(t, tags)
+
+		_, err = repo.Save(models.Quote{Id: "1", Text: "Text", Author: "Author", Tags: []string{"life", "wisdom"}})
+		require.NoError(t, err)
+		_, err = repo.Save(models.Quote{Id: "2", Text: "Text", Author: "Author", Tags: []string{"life"}})
+		require.NoError(t, err)
+		_, err = repo.Save(models.Quote{Id: "3", Text: "Text", Author: "Author", Tags: []string{"courage"}})
+		require.NoError(t, err)
+
+		tags, err = repo.ListTags()
+		require.NoError(t, err)
+		require.Equal(t, []models.TagCount{
+			{Tag: "life", Count: 2},
+			{Tag: "courage", Count: 1},
+			{Tag: "wisdom", Count: 1},
+		}, tags)
+
+		require.NoError(t, repo.Delete("3"))
+
+		tags, err = repo.ListTags()
+		require.NoError(t, err)
+		require.Equal(t, []models.TagCount{
+			{Tag: "life", Count: 2},
+			{Tag: "wisdom", Count: 1},
+		}, tags)
+	})
+
+	t.Run("Search_Stays_In_Sync", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.Save(models.Quote{Id: "1", Text: "Keep running towards your dreams.", Author: "Jane Doe"})
+		require.NoError(t, err)
+		_, err = repo.Save(models.Quote{Id: "2", Text: "L'été est une saison.", Author: "Renée Café"})
+		require.NoError(t, err)
+		_, err = repo.Save(models.Quote{Id: "3", Text: "Nothing in common here.", Author: "John Roe"})
+		require.NoError(t, err)
+
+		results, err := repo.Search("runs", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, "1", results[0].Quote.Id)
+		require.Greater(t, results[0].Score, 0.0)
+
+		results, err = repo.Search("ETE renee", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, "2", results[0].Quote.Id)
+
+		_, err = repo.Save(models.Quote{Id: "1", Text: "Walk slowly.", Author: "Jane Doe"})
+		require.NoError(t, err)
+		results, err = repo.Search("running", 10)
+		require.NoError(t, err)
+		require.Empty(t, results)
+
+		require.NoError(t, repo.Delete("2"))
+		results, err = repo.Search("ete", 10)
+		require.NoError(t, err)
+		require.Empty(t, results)
+	})
+}
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/helpers"
+	"github.com/danilobml/motivate/internal/models"
+)
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	// MaxDelay caps the backoff between attempts. A Retry-After longer than this ends the retries.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy reads SOURCE_MAX_ATTEMPTS, SOURCE_RETRY_BASE_DELAY and SOURCE_RETRY_MAX_DELAY (seconds).
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: helpers.GetenvInt("SOURCE_MAX_ATTEMPTS", 4),
+		BaseDelay:   helpers.GetenvDuration("SOURCE_RETRY_BASE_DELAY", 1),
+		MaxDelay:    helpers.GetenvDuration("SOURCE_RETRY_MAX_DELAY", 30),
+	}
+}
+
+// backoff is exponential with equal jitter: half of the doubled delay is fixed, the other half random.
+func (rp RetryPolicy) backoff(attempt int) time.Duration {
+	delay := rp.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > rp.MaxDelay {
+		delay = rp.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// RateLimiter is a token bucket allowing bursts of up to requests calls and, on average, requests per interval.
+type RateLimiter struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	perToken time.Duration
+	last     time.Time
+}
+
+func NewRateLimiter(requests int, per time.Duration) *RateLimiter {
+	return &RateLimiter{
+		capacity: float64(requests),
+		tokens:   float64(requests),
+		perToken: per / time.Duration(requests),
+		last:     time.Now(),
+	}
+}
+
+// Wait blocks until a request may be made or ctx is done.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		rl.mu.Lock()
+		now := time.Now()
+		rl.tokens = min(rl.capacity, rl.tokens+float64(now.Sub(rl.last))/float64(rl.perToken))
+		rl.last = now
+
+		if rl.tokens >= 1 {
+			rl.tokens--
+			rl.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - rl.tokens) * float64(rl.perToken))
+		rl.mu.Unlock()
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// RetryingSource wraps a QuoteSource, retrying transient failures (rate limiting,
+// network errors, 5xx) with backoff, and pacing requests through an optional limiter.
+type RetryingSource struct {
+	source  QuoteSource
+	policy  RetryPolicy
+	limiter *RateLimiter
+}
+
+func NewRetryingSource(source QuoteSource, policy RetryPolicy, limiter *RateLimiter) *RetryingSource {
+	return &RetryingSource{
+		source:  source,
+		policy:  policy,
+		limiter: limiter,
+	}
+}
+
+func (rs *RetryingSource) Name() string {
+	return rs.source.Name()
+}
+
+func (rs *RetryingSource) Fetch(ctx context.Context) ([]models.Quote, error) {
+	attempts := max(rs.policy.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		if rs.limiter != nil {
+			if err := rs.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		quotes, err := rs.source.Fetch(ctx)
+		if err == nil || attempt == attempts || !isTransient(err) {
+			return quotes, err
+		}
+
+		delay := rs.policy.backoff(attempt)
+
+		var sourceErr *SourceError
+		if errors.As(err, &sourceErr) && sourceErr.RetryAfter > 0 {
+			if sourceErr.RetryAfter > rs.policy.MaxDelay {
+				return nil, err
+			}
+			delay = max(delay, sourceErr.RetryAfter)
+		}
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func isTransient(err error) bool {
+	if errors.Is(err, errs.ErrRateLimited) || errors.Is(err, errs.ErrNetwork) {
+		return true
+	}
+
+	var sourceErr *SourceError
+	if errors.As(err, &sourceErr) {
+		return sourceErr.StatusCode >= 500 || sourceErr.StatusCode == http.StatusRequestTimeout
+	}
+
+	return false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,385 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/search"
+)
+
+// sqliteMigrations are applied in order. The index of the last applied
+// migration is stored in PRAGMA user_version, so entries must never be
+// edited or reordered once released - only appended.
+var sqliteMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS quotes (
+		id     TEXT PRIMARY KEY,
+		text   TEXT NOT NULL,
+		author TEXT NOT NULL
+	)`,
+	`ALTER TABLE quotes ADD COLUMN language TEXT NOT NULL DEFAULT ''`,
+	`CREATE TABLE tags (
+		id   INTEGER PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE
+	);
+	CREATE TABLE quote_tags (
+		quote_id TEXT NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+		tag_id   INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+		PRIMARY KEY (quote_id, tag_id)
+	);
+	CREATE INDEX quote_tags_tag_id ON quote_tags(tag_id)`,
+	`ALTER TABLE quotes ADD COLUMN source TEXT NOT NULL DEFAULT '';
+	ALTER TABLE quotes ADD COLUMN external_id TEXT NOT NULL DEFAULT '';
+	ALTER TABLE quotes ADD COLUMN batch_id TEXT NOT NULL DEFAULT '';
+	ALTER TABLE quotes ADD COLUMN created_by TEXT NOT NULL DEFAULT '';
+	ALTER TABLE quotes ADD COLUMN created_at TEXT NOT NULL DEFAULT '';
+	ALTER TABLE quotes ADD COLUMN updated_at TEXT NOT NULL DEFAULT '';
+	CREATE INDEX quotes_source ON quotes(source)`,
+	`ALTER TABLE quotes ADD COLUMN book TEXT NOT NULL DEFAULT ''`,
+}
+
+const sqliteQuoteColumns = "id, text, author, language, book, source, external_id, batch_id, created_by, created_at, updated_at"
+
+// SqliteQuoteRepository stores quotes in SQLite. Full-text search uses an in-process
+// index built from the table on open and kept in sync by Save and Delete.
+type SqliteQuoteRepository struct {
+	db     *sql.DB
+	search *search.Index
+}
+
+func NewSqliteQuoteRepository(path string) (*SqliteQuoteRepository, error) {
+	db, err := openSqlite(path, "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &SqliteQuoteRepository{db: db, search: search.NewIndex()}
+	if err := repo.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := repo.buildIndex(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// OpenSqliteQuoteRepositoryReadOnly opens an existing database read-only: it
+// is not created and no migrations are applied, so the file is left untouched.
+// A database whose schema is behind this version is rejected; Save and Delete fail.
+func OpenSqliteQuoteRepositoryReadOnly(path string) (*SqliteQuoteRepository, error) {
+	db, err := openSqlite(path, "mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version != len(sqliteMigrations) {
+		db.Close()
+		return nil, fmt.Errorf("sqlite database %s has schema version %d, expected %d; open it for writing once to migrate it", path, version, len(sqliteMigrations))
+	}
+
+	repo := &SqliteQuoteRepository{db: db, search: search.NewIndex()}
+	if err := repo.buildIndex(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func openSqlite(path, query string) (*sql.DB, error) {
+	// Escaping the path keeps a '?', '#' or '%' in it from being read as part of the URI.
+	dsn := url.URL{
+		Scheme:   "file",
+		Opaque:   (&url.URL{Path: path}).EscapedPath(),
+		RawQuery: query,
+	}
+
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY under concurrent requests.
+	db.SetMaxOpenConns(1)
+
+	return db, nil
+}
+
+func (sr *SqliteQuoteRepository) buildIndex() error {
+	quotes, err := sr.List()
+	if err != nil {
+		return fmt.Errorf("failed to build search index: %w", err)
+	}
+	for _, quote := range quotes {
+		sr.search.Add(quote.Id, quote.Text, quote.Author)
+	}
+
+	return nil
+}
+
+func (sr *SqliteQuoteRepository) migrate() error {
+	var version int
+	err := sr.db.QueryRow("PRAGMA user_version").Scan(&version)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		tx, err := sr.db.Begin()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(sqliteMigrations[i])
+		if err == nil {
+			_, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1))
+		}
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sr *SqliteQuoteRepository) Close() error {
+	return sr.db.Close()
+}
+
+func (sr *SqliteQuoteRepository) List() ([]models.Quote, error) {
+	rows, err := sr.db.Query("SELECT " + sqliteQuoteColumns + " FROM quotes ORDER BY rowid")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quotes := []models.Quote{}
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, *quote)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tags, err := sr.listTags("")
+	if err != nil {
+		return nil, err
+	}
+	for i := range quotes {
+		quotes[i].Tags = tags[quotes[i].Id]
+	}
+
+	return quotes, nil
+}
+
+func (sr *SqliteQuoteRepository) Find(id string) (*models.Quote, error) {
+	quote, err := scanQuote(sr.db.QueryRow("SELECT "+sqliteQuoteColumns+" FROM quotes WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := sr.listTags(id)
+	if err != nil {
+		return nil, err
+	}
+	quote.Tags = tags[id]
+
+	return quote, nil
+}
+
+// scanQuote reads the sqliteQuoteColumns of one row. Timestamps are stored as
+// RFC 3339 text, empty for quotes saved before they were recorded.
+func scanQuote(row interface{ Scan(dest ...any) error }) (*models.Quote, error) {
+	var quote models.Quote
+	var createdAt, updatedAt string
+
+	err := row.Scan(
+		&quote.Id, &quote.Text, &quote.Author, &quote.Language, &quote.Book,
+		&quote.Source, &quote.ExternalId, &quote.BatchId, &quote.CreatedBy, &createdAt, &updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if quote.CreatedAt, err = parseSqliteTime(createdAt); err != nil {
+		return nil, err
+	}
+	if quote.UpdatedAt, err = parseSqliteTime(updatedAt); err != nil {
+		return nil, err
+	}
+
+	return &quote, nil
+}
+
+func formatSqliteTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseSqliteTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// listTags returns tag names keyed by quote id, for a single quote or, with an empty id, all of them.
+func (sr *SqliteQuoteRepository) listTags(id string) (map[string][]string, error) {
+	rows, err := sr.db.Query(
+		`SELECT quote_tags.quote_id, tags.name FROM quote_tags
+		JOIN tags ON tags.id = quote_tags.tag_id
+		WHERE ? = '' OR quote_tags.quote_id = ?
+		ORDER BY tags.name`,
+		id, id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := map[string][]string{}
+	for rows.Next() {
+		var quoteId, name string
+		if err := rows.Scan(&quoteId, &name); err != nil {
+			return nil, err
+		}
+		tags[quoteId] = append(tags[quoteId], name)
+	}
+
+	return tags, rows.Err()
+}
+
+// Search ranks quotes by how well their text and author match query.
+func (sr *SqliteQuoteRepository) Search(query string, limit int) ([]models.SearchResult, error) {
+	results := []models.SearchResult{}
+	for _, hit := range sr.search.Search(query, limit) {
+		quote, err := sr.Find(hit.Id)
+		if errors.Is(err, errs.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, models.SearchResult{Quote: *quote, Score: hit.Score})
+	}
+
+	return results, nil
+}
+
+// ListTags counts the quotes carrying each tag, most used first.
+func (sr *SqliteQuoteRepository) ListTags() ([]models.TagCount, error) {
+	rows, err := sr.db.Query(
+		`SELECT tags.name, COUNT(*) AS count FROM quote_tags
+		JOIN tags ON tags.id = quote_tags.tag_id
+		GROUP BY tags.name
+		ORDER BY count DESC, tags.name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.TagCount{}
+	for rows.Next() {
+		var tag models.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func (sr *SqliteQuoteRepository) Save(quote models.Quote) (*models.Quote, error) {
+	tx, err := sr.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`INSERT INTO quotes (`+sqliteQuoteColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET text = excluded.text, author = excluded.author, language = excluded.language, book = excluded.book,
+			source = excluded.source, external_id = excluded.external_id, batch_id = excluded.batch_id,
+			created_by = excluded.created_by, created_at = excluded.created_at, updated_at = excluded.updated_at`,
+		quote.Id, quote.Text, quote.Author, quote.Language, quote.Book,
+		quote.Source, quote.ExternalId, quote.BatchId, quote.CreatedBy,
+		formatSqliteTime(quote.CreatedAt), formatSqliteTime(quote.UpdatedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec("DELETE FROM quote_tags WHERE quote_id = ?", quote.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tag := range quote.Tags {
+		_, err = tx.Exec("INSERT INTO tags (name) VALUES (?) ON CONFLICT(name) DO NOTHING", tag)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(
+			`INSERT INTO quote_tags (quote_id, tag_id) SELECT ?, id FROM tags WHERE name = ?
+			ON CONFLICT DO NOTHING`,
+			quote.Id, tag,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	sr.search.Add(quote.Id, quote.Text, quote.Author)
+
+	return &quote, nil
+}
+
+func (sr *SqliteQuoteRepository) Delete(id string) error {
+	result, err := sr.db.Exec("DELETE FROM quotes WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errs.ErrNotFound
+	}
+	sr.search.Remove(id)
+
+	return nil
+}
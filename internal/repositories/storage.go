@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NewQuoteRepositoryFromUrl builds the repository described by storageUrl:
+// "memory" (or empty) for the in-memory store, "sqlite://path/to/quotes.db" for SQLite
+// and "file://path/to/quotes.json" for a journaled JSON snapshot.
+func NewQuoteRepositoryFromUrl(storageUrl string) (QuoteRepository, error) {
+	scheme, path, _ := strings.Cut(storageUrl, "://")
+
+	switch scheme {
+	case "", "memory":
+		return NewInMemoryQuoteRepository(), nil
+	case "sqlite":
+		if path == "" {
+			return nil, fmt.Errorf("invalid storage %q: missing sqlite file path", storageUrl)
+		}
+		return NewSqliteQuoteRepository(path)
+	case "file":
+		if path == "" {
+			return nil, fmt.Errorf("invalid storage %q: missing snapshot file path", storageUrl)
+		}
+		return NewFileQuoteRepository(path, 0)
+	default:
+		return nil, fmt.Errorf("invalid storage %q: unsupported scheme %q", storageUrl, scheme)
+	}
+}
+
+// OpenQuoteRepositoryReadOnly opens storageUrl like NewQuoteRepositoryFromUrl
+// for a one-off read such as an export or a seed dry run, without writing to it:
+// a file:// store is loaded without being compacted and a sqlite:// database is
+// opened read-only, without migrations. Memory storage is rejected because it
+// always starts out empty.
+func OpenQuoteRepositoryReadOnly(storageUrl string) (QuoteRepository, error) {
+	scheme, path, _ := strings.Cut(storageUrl, "://")
+
+	switch scheme {
+	case "", "memory":
+		return nil, fmt.Errorf("invalid storage %q: memory storage starts empty, use sqlite:// or file://", storageUrl)
+	case "sqlite":
+		if path == "" {
+			return nil, fmt.Errorf("invalid storage %q: missing sqlite file path", storageUrl)
+		}
+		return OpenSqliteQuoteRepositoryReadOnly(path)
+	case "file":
+		if path == "" {
+			return nil, fmt.Errorf("invalid storage %q: missing snapshot file path", storageUrl)
+		}
+		return OpenFileQuoteRepositoryReadOnly(path)
+	default:
+		return NewQuoteRepositoryFromUrl(storageUrl)
+	}
+}
@@ -1,44 +1,62 @@
 package repositories
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
-	"io"
+	"context"
 	"net/http"
+	"strings"
 
+	"github.com/danilobml/motivate/internal/errs"
 	"github.com/danilobml/motivate/internal/models"
 )
 
 type ZenQuoteRepository struct {
 	BaseUrl string
+	Client  *http.Client
 }
 
-func NewZenQuoteRepository(baseUrl string) *ZenQuoteRepository {
+// NewZenQuoteRepository uses client for requests, or NewSourceHTTPClient() if it is nil.
+func NewZenQuoteRepository(baseUrl string, client *http.Client) *ZenQuoteRepository {
+	if client == nil {
+		client = NewSourceHTTPClient()
+	}
+
 	return &ZenQuoteRepository{
 		BaseUrl: baseUrl,
+		Client:  client,
 	}
 }
 
-func (zr *ZenQuoteRepository) GetZenquotesFromApi() ([]models.ZenQuote, error) {
-	resp, err := http.Get(zr.BaseUrl)
+func (zr *ZenQuoteRepository) Name() string {
+	return "zenquotes"
+}
+
+func (zr *ZenQuoteRepository) Fetch(ctx context.Context) ([]models.Quote, error) {
+	zenQuotes, err := zr.GetZenquotesFromApi(ctx)
 	if err != nil {
-		message := fmt.Sprintf("Failed to fetch quotes: %s", err)
-		return nil, errors.New(message)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		message := fmt.Sprintf("Failed to read response body: %s", err)
-		return nil, errors.New(message)
+	quotes := []models.Quote{}
+	for _, zenQuote := range zenQuotes {
+		quotes = append(quotes, models.Quote{
+			Text:   zenQuote.Text,
+			Author: zenQuote.Author,
+		})
 	}
 
+	return quotes, nil
+}
+
+func (zr *ZenQuoteRepository) GetZenquotesFromApi(ctx context.Context) ([]models.ZenQuote, error) {
 	var zenQuotes []models.ZenQuote
-	err = json.Unmarshal(body, &zenQuotes)
+	err := fetchJSON(ctx, zr.Client, zr.Name(), zr.BaseUrl, &zenQuotes)
 	if err != nil {
-		message := fmt.Sprintf("Failed to read response body: %s", err)
-		return nil, errors.New(message)
+		return nil, err
+	}
+
+	// When throttled, ZenQuotes may answer 200 with a single placeholder quote instead of a 429.
+	if len(zenQuotes) == 1 && zenQuotes[0].Author == "zenquotes.io" && strings.HasPrefix(zenQuotes[0].Text, "Too many requests") {
+		return nil, &SourceError{Source: zr.Name(), StatusCode: http.StatusOK, Err: errs.ErrRateLimited}
 	}
 
 	return zenQuotes, nil
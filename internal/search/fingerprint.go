@@ -0,0 +1,46 @@
+package search
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Fingerprint identifies a text regardless of case, diacritics, punctuation and whitespace:
+// "Stay hungry, stay foolish." and "stay HUNGRY  stay foolish!" share a fingerprint.
+func Fingerprint(text string) string {
+	sum := sha256.Sum256([]byte(strings.Join(Words(text), " ")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Similarity compares two texts by the character trigrams of their folded words,
+// returning the Dice coefficient: 1 for identical texts, 0 for nothing in common.
+func Similarity(a, b string) float64 {
+	x, y := trigrams(a), trigrams(b)
+	if len(x) == 0 && len(y) == 0 {
+		return 1
+	}
+	if len(x) == 0 || len(y) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for gram := range x {
+		if y[gram] {
+			shared++
+		}
+	}
+
+	return 2 * float64(shared) / float64(len(x)+len(y))
+}
+
+func trigrams(text string) map[string]bool {
+	runes := []rune(" " + strings.Join(Words(text), " ") + " ")
+
+	grams := map[string]bool{}
+	for i := 0; i+3 <= len(runes); i++ {
+		grams[string(runes[i:i+3])] = true
+	}
+
+	return grams
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// BM25 parameters: k1 dampens repeated terms, b controls document length normalization.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+type Hit struct {
+	Id    string
+	Score float64
+}
+
+type document struct {
+	length int
+	terms  map[string]int
+}
+
+// Index is an in-memory inverted index ranking documents with BM25. It is safe for concurrent use.
+type Index struct {
+	mu          sync.RWMutex
+	docs        map[string]document
+	postings    map[string]map[string]int
+	totalLength int
+}
+
+func NewIndex() *Index {
+	return &Index{
+		docs:     map[string]document{},
+		postings: map[string]map[string]int{},
+	}
+}
+
+// Add indexes the given texts under id, replacing whatever was indexed for it before.
+func (ix *Index) Add(id string, texts ...string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+
+	doc := document{terms: map[string]int{}}
+	for _, text := range texts {
+		for _, term := range Terms(text) {
+			doc.terms[term]++
+			doc.length++
+		}
+	}
+
+	for term, frequency := range doc.terms {
+		if ix.postings[term] == nil {
+			ix.postings[term] = map[string]int{}
+		}
+		ix.postings[term][id] = frequency
+	}
+	ix.docs[id] = doc
+	ix.totalLength += doc.length
+}
+
+func (ix *Index) Remove(id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+}
+
+func (ix *Index) remove(id string) {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+
+	for term := range doc.terms {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	delete(ix.docs, id)
+	ix.totalLength -= doc.length
+}
+
+// Search returns up to limit documents matching any term of query, best first.
+func (ix *Index) Search(query string, limit int) []Hit {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	if len(ix.docs) == 0 {
+		return []Hit{}
+	}
+
+	n := float64(len(ix.docs))
+	averageLength := float64(ix.totalLength) / n
+
+	scores := map[string]float64{}
+	for _, term := range uniqueTerms(query) {
+		postings := ix.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+		for id, frequency := range postings {
+			tf := float64(frequency)
+			length := float64(ix.docs[id].length)
+			scores[id] += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*length/averageLength))
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{Id: id, Score: score})
+	}
+	slices.SortFunc(hits, func(x, y Hit) int {
+		if x.Score != y.Score {
+			if x.Score > y.Score {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(x.Id, y.Id)
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits
+}
+
+func uniqueTerms(query string) []string {
+	terms := Terms(query)
+	slices.Sort(terms)
+	return slices.Compact(terms)
+}
@@ -0,0 +1,51 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"but": true, "by": true, "for": true, "if": true, "in": true, "into": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "so": true, "such": true, "that": true,
+	"the": true, "their": true, "then": true, "there": true, "these": true, "they": true,
+	"this": true, "to": true, "was": true, "will": true, "with": true,
+}
+
+// Fold lowercases s and strips diacritics: "Été" -> "ete".
+func Fold(s string) string {
+	folder := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+	folded, _, err := transform.String(folder, s)
+	if err != nil {
+		folded = s
+	}
+
+	return strings.ToLower(folded)
+}
+
+// Words splits folded text into runs of letters and digits.
+func Words(s string) []string {
+	return strings.FieldsFunc(Fold(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Terms turns text into index terms: folded words, minus stop words and
+// single letters (elisions like the l in "l'ete"), stemmed.
+func Terms(s string) []string {
+	terms := []string{}
+	for _, word := range Words(s) {
+		if stopWords[word] || utf8.RuneCountInString(word) == 1 {
+			continue
+		}
+		terms = append(terms, Stem(word))
+	}
+	return terms
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/search"
+)
+
+// Texts at least this similar (see search.Similarity) count as the same quote.
+const nearDuplicateThreshold = 0.9
+
+// duplicateCandidates bounds how many search hits are compared against a new text.
+const duplicateCandidates = 10
+
+// storeMu makes each duplicate check and the save that follows it one step, so
+// two identical quotes submitted at once cannot both pass the check. It is held
+// per quote, by /add, updates and every import or sync record.
+var storeMu sync.Mutex
+
+// importCreator is recorded as the creator of quotes stored by seeding and syncing.
+const importCreator = "system"
+
+// ImportReport summarizes a bulk load into the quote repository. BatchId is
+// recorded on every quote the load inserted. In a dry run Inserted counts the
+// quotes that would have been stored.
+type ImportReport struct {
+	BatchId    string          `json:"batch_id"`
+	DryRun     bool            `json:"dry_run,omitempty"`
+	Inserted   int             `json:"inserted"`
+	Updated    int             `json:"updated"`
+	Duplicates int             `json:"duplicates"`
+	Conflicts  int             `json:"conflicts"`
+	Invalid    int             `json:"invalid"`
+	Problems   []RecordProblem `json:"problems,omitempty"`
+}
+
+func newImportReport() *ImportReport {
+	return &ImportReport{BatchId: uuid.New().String()}
+}
+
+// findDuplicate returns a stored quote, other than excludeId, whose text is the same
+// as text up to case, punctuation and whitespace, or nearly the same. It returns nil if none is.
+func findDuplicate(repo repositories.QuoteRepository, text, excludeId string) (*models.Quote, error) {
+	var candidates []models.Quote
+
+	if len(search.Terms(text)) == 0 {
+		// Nothing to search for (only stop words): compare against everything.
+		quotes, err := repo.List()
+		if err != nil {
+			return nil, err
+		}
+		candidates = quotes
+	} else {
+		results, err := repo.Search(text, duplicateCandidates)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			candidates = append(candidates, result.Quote)
+		}
+	}
+
+	fingerprint := search.Fingerprint(text)
+	for _, candidate := range candidates {
+		if candidate.Id == excludeId {
+			continue
+		}
+		if search.Fingerprint(candidate.Text) == fingerprint || search.Similarity(candidate.Text, text) >= nearDuplicateThreshold {
+			return &candidate, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func duplicateError(duplicate *models.Quote) error {
+	return fmt.Errorf("%w: duplicate of quote %s", errs.ErrAlreadyExists, duplicate.Id)
+}
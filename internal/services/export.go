@@ -0,0 +1,40 @@
+package services
+
+import (
+	"io"
+
+	"github.com/danilobml/motivate/internal/formats"
+)
+
+// ExportQuotes writes every quote matching filter to w in format, in creation
+// order, and reports how many were written. The output can be seeded back with SeedDbFromFile.
+func (qs *QuoteService) ExportQuotes(w io.Writer, format formats.Format, filter QuoteFilter) (int, error) {
+	writer, err := formats.NewWriter(w, format)
+	if err != nil {
+		return 0, err
+	}
+
+	return qs.WriteQuotes(writer, filter)
+}
+
+// WriteQuotes is ExportQuotes for a writer the caller built, e.g. a
+// formats.FortuneWriter whose index is needed afterwards. It closes writer.
+func (qs *QuoteService) WriteQuotes(writer formats.QuoteWriter, filter QuoteFilter) (int, error) {
+	quotes, err := qs.quoteRepository.List()
+	if err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for _, quote := range quotes {
+		if !filter.Matches(quote) {
+			continue
+		}
+		if err := writer.Write(quote); err != nil {
+			return written, err
+		}
+		written++
+	}
+
+	return written, writer.Close()
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/danilobml/motivate/internal/errs"
+)
+
+type JobState string
+
+const (
+	JobQueued    JobState = "queued"
+	JobRunning   JobState = "running"
+	JobCompleted JobState = "completed"
+	JobFailed    JobState = "failed"
+	JobCancelled JobState = "cancelled"
+)
+
+// ImportJob is the progress of an import running in the background. Skipped
+// counts duplicates and Errors invalid records, as in ImportReport; Problems
+// lists the invalid records once the job has finished.
+type ImportJob struct {
+	Id         string          `json:"id"`
+	State      JobState        `json:"state"`
+	FileName   string          `json:"file_name,omitempty"`
+	DryRun     bool            `json:"dry_run,omitempty"`
+	BatchId    string          `json:"batch_id,omitempty"`
+	Processed  int             `json:"processed"`
+	Inserted   int             `json:"inserted"`
+	Skipped    int             `json:"skipped"`
+	Errors     int             `json:"errors"`
+	Problems   []RecordProblem `json:"problems,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  time.Time       `json:"started_at,omitzero"`
+	FinishedAt time.Time       `json:"finished_at,omitzero"`
+}
+
+func (j *ImportJob) Finished() bool {
+	return j.State == JobCompleted || j.State == JobFailed || j.State == JobCancelled
+}
+
+func (j *ImportJob) setCounts(report ImportReport) {
+	j.BatchId = report.BatchId
+	j.Processed = report.Inserted + report.Updated + report.Duplicates + report.Conflicts + report.Invalid
+	j.Inserted = report.Inserted
+	j.Skipped = report.Duplicates + report.Conflicts
+	j.Errors = report.Invalid
+}
+
+type importJob struct {
+	ImportJob
+	cancel context.CancelFunc
+}
+
+// ImportJobService runs file imports in the background, at most concurrency at
+// a time, and keeps finished jobs around for retention so their outcome can be read.
+type ImportJobService struct {
+	quoteService *QuoteService
+	retention    time.Duration
+	slots        chan struct{}
+
+	ctx  context.Context
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+
+	mu   sync.Mutex
+	jobs map[string]*importJob
+}
+
+func NewImportJobService(quoteService *QuoteService, concurrency int, retention time.Duration) *ImportJobService {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+
+	return &ImportJobService{
+		quoteService: quoteService,
+		retention:    retention,
+		slots:        make(chan struct{}, concurrency),
+		ctx:          ctx,
+		stop:         stop,
+		jobs:         map[string]*importJob{},
+	}
+}
+
+// Start queues an import of filePath and returns the new job. The job owns
+// filePath and removes it when it ends. fileName is only reported back.
+func (js *ImportJobService) Start(filePath, fileName, source string, options SeedOptions) ImportJob {
+	ctx, cancel := context.WithCancel(js.ctx)
+	job := &importJob{
+		ImportJob: ImportJob{
+			Id:        uuid.New().String(),
+			State:     JobQueued,
+			FileName:  fileName,
+			DryRun:    options.DryRun,
+			CreatedAt: time.Now().UTC(),
+		},
+		cancel: cancel,
+	}
+
+	js.mu.Lock()
+	js.prune()
+	js.jobs[job.Id] = job
+	snapshot := job.snapshot()
+	js.mu.Unlock()
+
+	js.wg.Add(1)
+	go js.run(ctx, job, filePath, source, options)
+
+	return snapshot
+}
+
+func (js *ImportJobService) run(ctx context.Context, job *importJob, filePath, source string, options SeedOptions) {
+	defer js.wg.Done()
+	defer job.cancel()
+	defer os.Remove(filePath)
+
+	select {
+	case js.slots <- struct{}{}:
+		defer func() { <-js.slots }()
+	case <-ctx.Done():
+		js.finish(job, nil, ctx.Err())
+		return
+	}
+
+	js.update(job, func(j *ImportJob) {
+		j.State = JobRunning
+		j.StartedAt = time.Now().UTC()
+	})
+
+	options.Progress = func(report ImportReport) {
+		js.update(job, func(j *ImportJob) { j.setCounts(report) })
+	}
+	report, err := js.quoteService.ImportFile(ctx, filePath, source, options)
+	js.finish(job, report, err)
+}
+
+func (js *ImportJobService) update(job *importJob, change func(j *ImportJob)) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	change(&job.ImportJob)
+}
+
+func (js *ImportJobService) finish(job *importJob, report *ImportReport, err error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	if report != nil {
+		job.setCounts(*report)
+		job.Problems = report.Problems
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		job.State = JobCancelled
+	case err != nil:
+		job.State = JobFailed
+		job.Error = err.Error()
+	default:
+		job.State = JobCompleted
+	}
+	job.FinishedAt = time.Now().UTC()
+
+	log.Printf("Import job %s %s. Quotes loaded: %d. Skipped: %d. Invalid: %d.", job.Id, job.State, job.Inserted, job.Skipped, job.Errors)
+}
+
+func (js *ImportJobService) Get(id string) (ImportJob, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	job, ok := js.jobs[id]
+	if !ok {
+		return ImportJob{}, errs.ErrNotFound
+	}
+
+	return job.snapshot(), nil
+}
+
+// Cancel stops a queued or running job. The job reports cancelled once the
+// import has stopped; quotes it stored until then are kept under its batch_id.
+func (js *ImportJobService) Cancel(id string) (ImportJob, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	job, ok := js.jobs[id]
+	if !ok {
+		return ImportJob{}, errs.ErrNotFound
+	}
+	if job.Finished() {
+		return job.snapshot(), errs.ErrJobFinished
+	}
+
+	job.cancel()
+
+	return job.snapshot(), nil
+}
+
+// Stop cancels every job and waits for them to end.
+func (js *ImportJobService) Stop() {
+	js.stop()
+	js.wg.Wait()
+}
+
+// prune forgets jobs that finished more than retention ago. Callers hold js.mu.
+func (js *ImportJobService) prune() {
+	cutoff := time.Now().Add(-js.retention)
+	for id, job := range js.jobs {
+		if job.Finished() && job.FinishedAt.Before(cutoff) {
+			delete(js.jobs, id)
+		}
+	}
+}
+
+func (j *importJob) snapshot() ImportJob {
+	snapshot := j.ImportJob
+	snapshot.Problems = slices.Clone(j.Problems)
+	return snapshot
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Email is a message for Mailer. HTML is optional; when it is set the message
+// carries both bodies as multipart/alternative and clients pick the one they show.
+// FromName is shown as the sender, while the address stays the configured one;
+// ReplyTo, if set, is where answers go.
+type Email struct {
+	To       []string
+	FromName string
+	ReplyTo  string
+	Subject  string
+	Text     string
+	HTML     string
+}
+
+// ComposeMessage renders email as an RFC 5322 message sent by from: UTF-8
+// headers are encoded (RFC 2047), bodies are quoted-printable and every line
+// ends in CRLF.
+func ComposeMessage(from string, email Email) ([]byte, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+
+	recipients := make([]string, len(email.To))
+	for i, to := range email.To {
+		recipient, err := mail.ParseAddress(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", to, err)
+		}
+		recipients[i] = recipient.String()
+	}
+
+	if email.FromName != "" {
+		sender.Name = email.FromName
+	}
+
+	var message bytes.Buffer
+	writeHeader(&message, "From", sender.String())
+	writeHeader(&message, "To", strings.Join(recipients, ", "))
+	if email.ReplyTo != "" {
+		replyTo, err := mail.ParseAddress(email.ReplyTo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reply-to address %q: %w", email.ReplyTo, err)
+		}
+		writeHeader(&message, "Reply-To", replyTo.String())
+	}
+	writeHeader(&message, "Subject", mime.QEncoding.Encode("utf-8", email.Subject))
+	writeHeader(&message, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&message, "Message-ID", messageId(sender.Address))
+	writeHeader(&message, "MIME-Version", "1.0")
+
+	if email.HTML == "" {
+		writeHeader(&message, "Content-Type", "text/plain; charset=utf-8")
+		writeHeader(&message, "Content-Transfer-Encoding", "quoted-printable")
+		message.WriteString("\r\n")
+		if err := writeQuotedPrintable(&message, email.Text); err != nil {
+			return nil, err
+		}
+		return message.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&message)
+	writeHeader(&message, "Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": parts.Boundary()}))
+	message.WriteString("\r\n")
+
+	// Clients show the last alternative they understand, so the richer one goes last.
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", email.Text},
+		{"text/html; charset=utf-8", email.HTML},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(writer, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	return message.Bytes(), nil
+}
+
+func writeHeader(message *bytes.Buffer, name, value string) {
+	message.WriteString(name + ": " + value + "\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	writer := quotedprintable.NewWriter(w)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// messageId makes a globally unique Message-ID in the sender's domain.
+func messageId(sender string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(sender, "@"); at >= 0 {
+		domain = sender[at+1:]
+	}
+	return "<" + uuid.New().String() + "@" + domain + ">"
+}
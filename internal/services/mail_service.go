@@ -8,7 +8,7 @@ import (
 )
 
 type Mailer interface {
-	SendMail(to []string, subject string, body string) error
+	SendMail(email Email) error
 }
 
 type MailService struct {}
@@ -17,7 +17,7 @@ func NewMailService() *MailService {
 	return &MailService{}
 }
 
-func (ms *MailService) SendMail(to []string, subject string, body string) error {
+func (ms *MailService) SendMail(email Email) error {
 	if os.Getenv("FROM_EMAIL") == "" ||
 		os.Getenv("FROM_EMAIL_PASSWORD") == "" ||
 		os.Getenv("FROM_EMAIL_SMTP") == "" ||
@@ -32,14 +32,17 @@ func (ms *MailService) SendMail(to []string, subject string, body string) error
 		os.Getenv("FROM_EMAIL_SMTP"),
 	)
 
-	message := "Subject: " + subject + "\n" + body
+	message, err := ComposeMessage(os.Getenv("FROM_EMAIL"), email)
+	if err != nil {
+		return err
+	}
 
-	err := smtp.SendMail(
+	err = smtp.SendMail(
 		os.Getenv("SMTP_ADDR"),
 		auth,
 		os.Getenv("FROM_EMAIL"),
-		to,
-		[]byte(message),
+		email.To,
+		message,
 	)
 
 	return err
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/models"
+)
+
+// GetQuoteOfTheDay deterministically picks the quote for the calendar day of date.
+//
+// Each quote gets a score from a hash of the day and its id, and the highest score
+// wins (rendezvous hashing), so adding or removing other quotes only changes the
+// pick if the new quote outscores it. To avoid repeats, days are grouped into
+// blocks of dailyWindow days: within a block every day excludes the earlier picks
+// of that block, and consecutive blocks draw from two disjoint halves of the
+// collection. Any two days less than dailyWindow apart therefore get different
+// quotes, as long as each half holds at least dailyWindow quotes.
+func (qs *QuoteService) GetQuoteOfTheDay(date time.Time) (*models.Quote, error) {
+	quotes, err := qs.quoteRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(quotes) == 0 {
+		return nil, errs.ErrEmpty
+	}
+
+	window := max(qs.dailyWindow, 1)
+	day := civilDay(date)
+	block := floorDiv(day, window)
+
+	candidates := []models.Quote{}
+	for _, quote := range quotes {
+		if int64(hashString(quote.Id)%2) == absInt64(block%2) {
+			candidates = append(candidates, quote)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = quotes
+	}
+
+	picked := map[string]bool{}
+	var pick models.Quote
+	for d := block * int64(window); d <= day; d++ {
+		if len(picked) == len(candidates) {
+			picked = map[string]bool{}
+		}
+
+		found := false
+		var best uint64
+		for _, quote := range candidates {
+			if picked[quote.Id] {
+				continue
+			}
+			score := hashString(strconv.FormatInt(d, 10) + ":" + quote.Id)
+			if !found || score > best || (score == best && quote.Id < pick.Id) {
+				found = true
+				best = score
+				pick = quote
+			}
+		}
+		picked[pick.Id] = true
+	}
+
+	return &pick, nil
+}
+
+// civilDay numbers the calendar day of t in its own location, counting from 1970-01-01.
+func civilDay(t time.Time) int64 {
+	year, month, day := t.Date()
+	return floorDiv(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix(), 24*60*60)
+}
+
+func floorDiv(a int64, b int) int64 {
+	q := a / int64(b)
+	if a%int64(b) != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+func absInt64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// hashString is FNV-1a followed by the splitmix64 finalizer, which spreads
+// inputs that differ only in a few characters (consecutive days, numeric ids).
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
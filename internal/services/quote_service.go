@@ -1,54 +1,227 @@
 package services
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"math/rand"
 	"os"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
 	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/formats"
+	"github.com/danilobml/motivate/internal/helpers"
 	"github.com/danilobml/motivate/internal/models"
 	"github.com/danilobml/motivate/internal/repositories"
 )
 
+type QuoteSort string
+
+const (
+	SortByCreated QuoteSort = "created"
+	SortByAuthor  QuoteSort = "author"
+)
+
+type ListOptions struct {
+	Page       int
+	Limit      int
+	Sort       QuoteSort
+	Descending bool
+}
+
+type QuotePage struct {
+	Quotes []models.Quote `json:"quotes"`
+	Page   int            `json:"page"`
+	Limit  int            `json:"limit"`
+	Total  int            `json:"total"`
+}
+
+// QuoteFilter constrains which quotes GetRandomQuote, ListQuotes and DeleteQuotes
+// act on. Zero values match everything.
+type QuoteFilter struct {
+	Author     string
+	Tag        string
+	Language   string
+	MinLength  int
+	MaxLength  int
+	ExcludeIds []string
+	Source     string
+	BatchId    string
+	CreatedBy  string
+}
+
+func (f QuoteFilter) Matches(quote models.Quote) bool {
+	if f.Author != "" && !strings.Contains(strings.ToLower(quote.Author), strings.ToLower(f.Author)) {
+		return false
+	}
+	if f.Tag != "" && !slices.ContainsFunc(quote.Tags, func(tag string) bool { return strings.EqualFold(tag, f.Tag) }) {
+		return false
+	}
+	if f.Language != "" && !strings.EqualFold(quote.Language, f.Language) {
+		return false
+	}
+	if f.Source != "" && !strings.EqualFold(quote.Source, f.Source) {
+		return false
+	}
+	if f.BatchId != "" && quote.BatchId != f.BatchId {
+		return false
+	}
+	if f.CreatedBy != "" && quote.CreatedBy != f.CreatedBy {
+		return false
+	}
+
+	length := utf8.RuneCountInString(quote.Text)
+	if f.MinLength > 0 && length < f.MinLength {
+		return false
+	}
+	if f.MaxLength > 0 && length > f.MaxLength {
+		return false
+	}
+
+	return !slices.Contains(f.ExcludeIds, quote.Id)
+}
+
+// QuoteUpdate holds the fields to change; nil fields are left untouched.
+type QuoteUpdate struct {
+	Text   *string
+	Author *string
+	Tags   *[]string
+}
+
 type QuoteService struct {
-	quoteRepository *repositories.InMemoryQuoteRepository
+	quoteRepository repositories.QuoteRepository
+	dailyWindow     int
+	shuffleBags     *shuffleBags
 }
 
-func NewQuoteService(repo *repositories.InMemoryQuoteRepository) *QuoteService {
+func NewQuoteService(repo repositories.QuoteRepository) *QuoteService {
 	return &QuoteService{
 		quoteRepository: repo,
+		dailyWindow:     helpers.GetenvInt("QUOTE_OF_THE_DAY_WINDOW", 30),
+		shuffleBags:     newShuffleBags(helpers.GetenvInt("SHUFFLE_BAG_CLIENTS", 10000)),
+	}
+}
+
+func (qs *QuoteService) GetRandomQuote(filter QuoteFilter) (*models.Quote, error) {
+	quotes, err := qs.matchingQuotes(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	index := rand.Intn(len(quotes))
+
+	return &quotes[index], nil
+}
+
+// GetNextQuoteForClient draws from the client's shuffle bag: it cycles through every
+// matching quote in random order before repeating any of them.
+func (qs *QuoteService) GetNextQuoteForClient(clientId string, filter QuoteFilter) (*models.Quote, error) {
+	quotes, err := qs.matchingQuotes(filter)
+	if err != nil {
+		return nil, err
 	}
+
+	quote := qs.shuffleBags.next(clientId, quotes)
+
+	return &quote, nil
+}
+
+// ShuffleBagClients reports how many clients currently have a shuffle bag.
+func (qs *QuoteService) ShuffleBagClients() int {
+	return qs.shuffleBags.len()
 }
 
-func (qs *QuoteService) GetRandomQuote() (*models.Quote, error) {
-	quotes := qs.quoteRepository.List()
+func (qs *QuoteService) matchingQuotes(filter QuoteFilter) ([]models.Quote, error) {
+	quotes, err := qs.quoteRepository.List()
+	if err != nil {
+		return nil, err
+	}
 
 	if len(quotes) == 0 {
 		return nil, errs.ErrEmpty
 	}
 
-	index := rand.Intn(len(quotes))
+	quotes = slices.DeleteFunc(quotes, func(quote models.Quote) bool {
+		return !filter.Matches(quote)
+	})
+	if len(quotes) == 0 {
+		return nil, errs.ErrNoMatch
+	}
 
-	return &quotes[index], nil
+	return quotes, nil
 }
 
-func (qs *QuoteService) CreateQuote(text, author string) (*models.Quote, error) {
+func (qs *QuoteService) GetQuote(id string) (*models.Quote, error) {
+	return qs.quoteRepository.Find(id)
+}
+
+func (qs *QuoteService) ListQuotes(filter QuoteFilter, options ListOptions) (*QuotePage, error) {
+	quotes, err := qs.quoteRepository.List()
+	if err != nil {
+		return nil, err
+	}
+
+	quotes = slices.DeleteFunc(quotes, func(quote models.Quote) bool {
+		return !filter.Matches(quote)
+	})
+
+	// List is in insertion order, which is creation order.
+	if options.Sort == SortByAuthor {
+		slices.SortStableFunc(quotes, func(a, b models.Quote) int {
+			return strings.Compare(strings.ToLower(a.Author), strings.ToLower(b.Author))
+		})
+	}
+	if options.Descending {
+		slices.Reverse(quotes)
+	}
+
+	total := len(quotes)
+	start := min((options.Page-1)*options.Limit, total)
+	end := min(start+options.Limit, total)
+
+	return &QuotePage{
+		Quotes: quotes[start:end],
+		Page:   options.Page,
+		Limit:  options.Limit,
+		Total:  total,
+	}, nil
+}
+
+// CreateQuote stores a quote added through the API. createdBy identifies the caller and may be empty.
+func (qs *QuoteService) CreateQuote(text, author string, tags []string, createdBy string) (*models.Quote, error) {
 	id := uuid.New().String()
 
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	duplicate, err := findDuplicate(qs.quoteRepository, text, "")
+	if err != nil {
+		return nil, err
+	}
+	if duplicate != nil {
+		return nil, duplicateError(duplicate)
+	}
+
 	if author == "" {
 		author = "Unknown"
 	}
 
+	now := time.Now().UTC()
 	newQuote := models.Quote{
-		Id: id,
-		Text: text,
-		Author: author,
+		Id:        id,
+		Text:      text,
+		Author:    author,
+		Tags:      models.NormalizeTags(tags),
+		Source:    models.SourceApi,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 
 	quote, err := qs.quoteRepository.Save(newQuote)
@@ -59,34 +232,132 @@ func (qs *QuoteService) CreateQuote(text, author string) (*models.Quote, error)
 	return quote, nil
 }
 
+func (qs *QuoteService) UpdateQuote(id string, update QuoteUpdate) (*models.Quote, error) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
 
-func (qs *QuoteService) SeedDbFromFile(filePath string) error {
-	start := time.Now()
-
-	file, err := os.Open(filePath)
+	quote, err := qs.quoteRepository.Find(id)
 	if err != nil {
-		message := fmt.Sprintf("Failed to open json file: %s", err.Error())
-		return errors.New(message)
+		return nil, err
 	}
-	defer file.Close()
 
-	quotes := []models.Quote{}
-	err = json.NewDecoder(file).Decode(&quotes)
+	if update.Text != nil {
+		duplicate, err := findDuplicate(qs.quoteRepository, *update.Text, id)
+		if err != nil {
+			return nil, err
+		}
+		if duplicate != nil {
+			return nil, duplicateError(duplicate)
+		}
+		quote.Text = *update.Text
+	}
+	if update.Author != nil {
+		quote.Author = *update.Author
+		if quote.Author == "" {
+			quote.Author = "Unknown"
+		}
+	}
+	if update.Tags != nil {
+		quote.Tags = models.NormalizeTags(*update.Tags)
+	}
+	quote.UpdatedAt = time.Now().UTC()
+
+	return qs.quoteRepository.Save(*quote)
+}
+
+func (qs *QuoteService) SearchQuotes(query string, limit int) ([]models.SearchResult, error) {
+	return qs.quoteRepository.Search(query, limit)
+}
+
+func (qs *QuoteService) ListTags() ([]models.TagCount, error) {
+	return qs.quoteRepository.ListTags()
+}
+
+func (qs *QuoteService) DeleteQuote(id string) error {
+	return qs.quoteRepository.Delete(id)
+}
+
+// DeleteQuotes removes every quote matching filter, e.g. all quotes from one
+// source or import batch, and reports how many were deleted.
+func (qs *QuoteService) DeleteQuotes(filter QuoteFilter) (int, error) {
+	quotes, err := qs.quoteRepository.List()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
+	deleted := 0
 	for _, quote := range quotes {
-		newQuote := models.Quote{
-			Id: quote.Id,
-			Text: quote.Text,
-			Author: quote.Author,
+		if !filter.Matches(quote) {
+			continue
 		}
-		qs.quoteRepository.Save(newQuote)
+		err := qs.quoteRepository.Delete(quote.Id)
+		if errors.Is(err, errs.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// SeedDbFromFile loads quotes from a JSON, NDJSON, CSV, YAML, fortune, Kindle
+// clippings or Goodreads file, optionally gzipped. Unless options.Format is set,
+// the format comes from the file name, or is sniffed from the content.
+func (qs *QuoteService) SeedDbFromFile(filePath string, options SeedOptions) (*ImportReport, error) {
+	start := time.Now()
+
+	report, err := qs.ImportFile(context.Background(), filePath, models.SourceFile, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.DryRun {
+		elapsed := time.Since(start)
+		log.Printf("Quotes DB seeded successfully from file! Quotes loaded: %d. Duplicates skipped: %d. Id conflicts skipped: %d. Invalid skipped: %d. Elapsed time: %v.\n", report.Inserted, report.Duplicates, report.Conflicts, report.Invalid, elapsed)
+		logImportProblems(report)
+	}
+
+	return report, nil
+}
+
+// ImportFile is SeedDbFromFile without the logging, recording source as the
+// provenance of quotes that do not carry their own. On error the report, if
+// any, tells how far the import got.
+func (qs *QuoteService) ImportFile(ctx context.Context, filePath string, source string, options SeedOptions) (*ImportReport, error) {
+	if options.Strict && !options.DryRun {
+		// Validate the whole file first so a bad record leaves the store untouched.
+		dryRun := options
+		dryRun.DryRun = true
+		dryRun.Progress = nil
+		_, err := qs.importFile(ctx, filePath, source, dryRun)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return qs.importFile(ctx, filePath, source, options)
+}
+
+func (qs *QuoteService) importFile(ctx context.Context, filePath string, source string, options SeedOptions) (*ImportReport, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		message := fmt.Sprintf("Failed to open seed file: %s", err.Error())
+		return nil, errors.New(message)
 	}
+	defer file.Close()
 
-	elapsed := time.Since(start)
-	log.Printf("Quotes DB seeded successfully from file! Quotes loaded: %d. Elapsed time: %v.\n", len(quotes), elapsed)
+	var reader formats.QuoteReader
+	if options.Format != "" {
+		reader, err = formats.NewReaderAs(file, options.Format)
+	} else {
+		reader, err = formats.NewReader(file, filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	return importQuotes(ctx, qs.quoteRepository, reader, source, options)
 }
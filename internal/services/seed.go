@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/formats"
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/search"
+)
+
+// maxReportedProblems bounds the problems kept in an ImportReport; Invalid still counts them all.
+const maxReportedProblems = 1000
+
+// maxLoggedProblems bounds the skipped records logged after a seed.
+const maxLoggedProblems = 10
+
+type SeedOptions struct {
+	// DryRun validates and reports without storing anything.
+	DryRun bool
+	// Strict stops at the first bad record. Without DryRun, the whole input is
+	// validated before anything is stored, so a failed import leaves the store untouched.
+	Strict bool
+	// Format overrides detection from the file name and content.
+	Format formats.Format
+	// Progress, when set, is called before each record with the counts so far.
+	Progress func(report ImportReport)
+	// Upsert updates a stored quote with the same source and external id in
+	// place, so edits made upstream are picked up on the next sync.
+	Upsert bool
+}
+
+// RecordProblem explains why one record of an import was skipped. Record is its
+// 1-based position in the input.
+type RecordProblem struct {
+	Record int    `json:"record"`
+	Reason string `json:"reason"`
+}
+
+// importRecord carries the rules NewQuoteRequest enforces on /add.
+type importRecord struct {
+	Text   string   `validate:"required,max=512"`
+	Author string   `validate:"max=128"`
+	Tags   []string `validate:"max=16,dive,required,max=32"`
+}
+
+// validateQuote checks a quote against the same limits as /add and explains the first violation.
+func validateQuote(validate *validator.Validate, quote models.Quote) error {
+	err := validate.Struct(importRecord{Text: quote.Text, Author: quote.Author, Tags: quote.Tags})
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	fieldErr := validationErrors[0]
+	field := strings.ToLower(fieldErr.Field())
+	switch {
+	case fieldErr.Tag() == "required":
+		return fmt.Errorf("%w: %s is required", errs.ErrInvalidQuote, field)
+	case fieldErr.Tag() == "max" && fieldErr.Kind().String() == "slice":
+		return fmt.Errorf("%w: more than %s %s", errs.ErrInvalidQuote, fieldErr.Param(), field)
+	case fieldErr.Tag() == "max":
+		return fmt.Errorf("%w: %s is longer than %s characters", errs.ErrInvalidQuote, field, fieldErr.Param())
+	default:
+		return fmt.Errorf("%w: %s failed %s", errs.ErrInvalidQuote, field, fieldErr.Tag())
+	}
+}
+
+// cleanImportedQuote trims the fields and normalizes the tags the way /add does.
+func cleanImportedQuote(quote models.Quote) models.Quote {
+	quote.Text = strings.TrimSpace(quote.Text)
+	quote.Author = strings.TrimSpace(quote.Author)
+	if quote.Author == "" {
+		quote.Author = "Unknown"
+	}
+	quote.Tags = models.NormalizeTags(quote.Tags)
+
+	return quote
+}
+
+func (r *ImportReport) addProblem(record int, err error) {
+	r.Invalid++
+	r.listProblem(record, err.Error())
+}
+
+func (r *ImportReport) addConflict(record int, id string) {
+	r.Conflicts++
+	r.listProblem(record, fmt.Sprintf("id %q is already used by another quote", id))
+}
+
+func (r *ImportReport) listProblem(record int, reason string) {
+	if len(r.Problems) < maxReportedProblems {
+		r.Problems = append(r.Problems, RecordProblem{Record: record, Reason: reason})
+	}
+}
+
+// importQuotes validates and stores every quote from reader, recording source as
+// their provenance unless a record names its own (Kindle, Goodreads and exports do). Records that cannot be decoded or fail validation are
+// skipped and listed in the report, unless options.Strict makes them fatal. A record whose
+// id already belongs to a stored quote is skipped as a conflict rather than overwriting it.
+// A record with a created_at, as in an export, keeps its batch, creator and timestamps.
+// With options.Upsert, a record matching a stored quote by source and external id updates it instead.
+// Cancelling ctx stops the import; quotes stored until then are kept.
+func importQuotes(ctx context.Context, repo repositories.QuoteRepository, reader formats.QuoteReader, source string, options SeedOptions) (*ImportReport, error) {
+	report := newImportReport()
+	report.DryRun = options.DryRun
+
+	validate := validator.New()
+	// A dry run stores nothing, so repeats within the input are caught by fingerprint instead.
+	seen := map[string]bool{}
+	ids := map[string]bool{}
+
+	var stored map[string]models.Quote
+	if options.Upsert {
+		var err error
+		if stored, err = quotesByExternalId(repo); err != nil {
+			return report, err
+		}
+	}
+
+	// store checks a valid record against the collection and saves it. The lock
+	// keeps another import or /add from storing the same text in between.
+	store := func(record int, quote models.Quote) error {
+		storeMu.Lock()
+		defer storeMu.Unlock()
+
+		quoteSource := quote.Source
+		if quoteSource == "" {
+			quoteSource = source
+		}
+
+		if existing, ok := stored[externalIdKey(quoteSource, quote.ExternalId)]; ok && quote.ExternalId != "" {
+			updated, err := updateImportedQuote(repo, existing, quote, options.DryRun)
+			if err != nil {
+				return fmt.Errorf("record %d: failed to update quote: %w", record, err)
+			}
+			if updated == nil {
+				report.Duplicates++
+				return nil
+			}
+			stored[externalIdKey(quoteSource, quote.ExternalId)] = *updated
+			seen[search.Fingerprint(quote.Text)] = true
+			report.Updated++
+			return nil
+		}
+
+		duplicate, err := findDuplicate(repo, quote.Text, "")
+		if err != nil {
+			return err
+		}
+		fingerprint := search.Fingerprint(quote.Text)
+		if duplicate != nil || seen[fingerprint] {
+			report.Duplicates++
+			return nil
+		}
+		seen[fingerprint] = true
+
+		if quote.Id != "" {
+			taken, err := idTaken(repo, quote.Id)
+			if err != nil {
+				return err
+			}
+			if taken || ids[quote.Id] {
+				report.addConflict(record, quote.Id)
+				return nil
+			}
+			ids[quote.Id] = true
+		}
+
+		if options.DryRun {
+			report.Inserted++
+			return nil
+		}
+
+		// CSV and NDJSON exports often carry no ids, and API quotes only an external one.
+		id := quote.Id
+		if id == "" {
+			id = uuid.New().String()
+		}
+
+		newQuote := models.Quote{
+			Id:         id,
+			Text:       quote.Text,
+			Author:     quote.Author,
+			Tags:       quote.Tags,
+			Language:   quote.Language,
+			Book:       quote.Book,
+			Source:     quoteSource,
+			ExternalId: quote.ExternalId,
+			BatchId:    quote.BatchId,
+			CreatedBy:  quote.CreatedBy,
+			CreatedAt:  quote.CreatedAt,
+			UpdatedAt:  quote.UpdatedAt,
+		}
+		if newQuote.CreatedAt.IsZero() {
+			// A record without created_at is new here rather than restored from an export.
+			now := time.Now().UTC()
+			newQuote.BatchId = report.BatchId
+			newQuote.CreatedBy = importCreator
+			newQuote.CreatedAt = now
+			newQuote.UpdatedAt = now
+			if newQuote.ExternalId == "" {
+				newQuote.ExternalId = quote.Id
+			}
+		} else if newQuote.UpdatedAt.IsZero() {
+			newQuote.UpdatedAt = newQuote.CreatedAt
+		}
+		if _, err := repo.Save(newQuote); err != nil {
+			return fmt.Errorf("record %d: failed to save quote: %w", record, err)
+		}
+		report.Inserted++
+
+		return nil
+	}
+
+	for record := 1; ; record++ {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		if options.Progress != nil {
+			options.Progress(*report)
+		}
+
+		quote, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var recordErr *formats.RecordError
+		if errors.As(err, &recordErr) {
+			err = fmt.Errorf("%w: %s", errs.ErrInvalidQuote, recordErr.Err)
+		} else if err != nil {
+			return report, err
+		} else {
+			quote = cleanImportedQuote(quote)
+			err = validateQuote(validate, quote)
+		}
+		if err != nil {
+			if options.Strict {
+				return report, fmt.Errorf("record %d: %w", record, err)
+			}
+			report.addProblem(record, err)
+			continue
+		}
+
+		if err := store(record, quote); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+func externalIdKey(source, externalId string) string {
+	return source + "\x00" + externalId
+}
+
+// quotesByExternalId indexes the stored quotes that have an external id by source and that id.
+func quotesByExternalId(repo repositories.QuoteRepository) (map[string]models.Quote, error) {
+	quotes, err := repo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := map[string]models.Quote{}
+	for _, quote := range quotes {
+		if quote.ExternalId != "" {
+			stored[externalIdKey(quote.Source, quote.ExternalId)] = quote
+		}
+	}
+
+	return stored, nil
+}
+
+// updateImportedQuote copies the content of an upstream record onto the stored
+// quote it came from, keeping its id and provenance. It returns nil when nothing
+// changed, or when the new text duplicates another quote.
+func updateImportedQuote(repo repositories.QuoteRepository, existing, quote models.Quote, dryRun bool) (*models.Quote, error) {
+	if existing.Text == quote.Text && existing.Author == quote.Author && slices.Equal(existing.Tags, quote.Tags) &&
+		existing.Language == quote.Language && existing.Book == quote.Book {
+		return nil, nil
+	}
+
+	duplicate, err := findDuplicate(repo, quote.Text, existing.Id)
+	if err != nil || duplicate != nil {
+		return nil, err
+	}
+
+	existing.Text = quote.Text
+	existing.Author = quote.Author
+	existing.Tags = quote.Tags
+	existing.Language = quote.Language
+	existing.Book = quote.Book
+	existing.UpdatedAt = time.Now().UTC()
+
+	if dryRun {
+		return &existing, nil
+	}
+	return repo.Save(existing)
+}
+
+func idTaken(repo repositories.QuoteRepository, id string) (bool, error) {
+	_, err := repo.Find(id)
+	if errors.Is(err, errs.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func logImportProblems(report *ImportReport) {
+	for i, problem := range report.Problems {
+		if i == maxLoggedProblems {
+			log.Printf("...and %d more skipped records.", report.Invalid+report.Conflicts-maxLoggedProblems)
+			break
+		}
+		log.Printf("Skipped record %d: %s", problem.Record, problem.Reason)
+	}
+}
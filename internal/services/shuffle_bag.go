@@ -0,0 +1,120 @@
+package services
+
+import (
+	"container/list"
+	"hash/fnv"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+// shuffleBags remembers, per client, where it is in a random order of the
+// collection, so each client sees the whole collection before any quote
+// repeats. Only the most recently active clients are kept, and a bag has a
+// fixed size whatever the collection's, so memory is bounded by capacity alone.
+type shuffleBags struct {
+	mu       sync.Mutex
+	capacity int
+	recent   *list.List
+	bags     map[string]*list.Element
+}
+
+// shuffleBag describes the client's current cycle without storing it: the
+// order is the permutation of the candidates drawn from seed, and cursor
+// counts the quotes served. key identifies the candidates the cycle is over;
+// when they change (a quote is added or deleted, or another filter is used)
+// a new cycle starts.
+type shuffleBag struct {
+	clientId string
+	key      uint64
+	seed     int64
+	cursor   int
+	// swapped serves the first two positions in reverse, so a cycle never opens
+	// with the quote that closed the previous one.
+	swapped bool
+	last    string
+}
+
+func newShuffleBags(capacity int) *shuffleBags {
+	return &shuffleBags{
+		capacity: max(capacity, 1),
+		recent:   list.New(),
+		bags:     map[string]*list.Element{},
+	}
+}
+
+// next draws a quote for the client from candidates, which must not be empty.
+func (sb *shuffleBags) next(clientId string, candidates []models.Quote) models.Quote {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	bag := sb.get(clientId)
+
+	// The permutation is over the candidates sorted by id, so it does not depend on the order they come in.
+	candidates = slices.SortedFunc(slices.Values(candidates), func(a, b models.Quote) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	key := candidatesKey(candidates)
+	if key != bag.key || bag.cursor >= len(candidates) {
+		bag.key = key
+		bag.seed = rand.Int63()
+		bag.cursor = 0
+		bag.swapped = false
+	}
+
+	order := rand.New(rand.NewSource(bag.seed)).Perm(len(candidates))
+	if bag.cursor == 0 && len(order) > 1 && candidates[order[0]].Id == bag.last {
+		bag.swapped = true
+	}
+
+	position := bag.cursor
+	if bag.swapped && position < 2 {
+		position = 1 - position
+	}
+
+	pick := candidates[order[position]]
+	bag.cursor++
+	bag.last = pick.Id
+
+	return pick
+}
+
+// candidatesKey identifies a sorted candidate set; it changes when quotes are added or removed.
+func candidatesKey(candidates []models.Quote) uint64 {
+	hash := fnv.New64a()
+	for _, quote := range candidates {
+		hash.Write([]byte(quote.Id))
+		hash.Write([]byte{0})
+	}
+
+	return hash.Sum64()
+}
+
+func (sb *shuffleBags) get(clientId string) *shuffleBag {
+	if element, ok := sb.bags[clientId]; ok {
+		sb.recent.MoveToFront(element)
+		return element.Value.(*shuffleBag)
+	}
+
+	if sb.recent.Len() >= sb.capacity {
+		oldest := sb.recent.Back()
+		sb.recent.Remove(oldest)
+		delete(sb.bags, oldest.Value.(*shuffleBag).clientId)
+	}
+
+	bag := &shuffleBag{clientId: clientId}
+	sb.bags[clientId] = sb.recent.PushFront(bag)
+
+	return bag
+}
+
+func (sb *shuffleBags) len() int {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	return sb.recent.Len()
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/danilobml/motivate/internal/formats"
+	"github.com/danilobml/motivate/internal/repositories"
+)
+
+type SourceService struct {
+	quoteRepository repositories.QuoteRepository
+}
+
+func NewSourceService(quoteRepo repositories.QuoteRepository) *SourceService {
+	return &SourceService{
+		quoteRepository: quoteRepo,
+	}
+}
+
+func (ss *SourceService) SeedDbFromSource(ctx context.Context, source repositories.QuoteSource) (*ImportReport, error) {
+	start := time.Now()
+
+	fetched, err := source.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := importQuotes(ctx, ss.quoteRepository, formats.NewSliceReader(fetched), source.Name(), SeedOptions{Upsert: true})
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("Quotes DB seeded successfully from %s! Quotes loaded: %d. Quotes updated: %d. Duplicates skipped: %d. Id conflicts skipped: %d. Invalid skipped: %d. Elapsed time: %v.\n", source.Name(), report.Inserted, report.Updated, report.Duplicates, report.Conflicts, report.Invalid, elapsed)
+	logImportProblems(report)
+
+	return report, nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/danilobml/motivate/internal/repositories"
+)
+
+type SourceStatus struct {
+	Source      string     `json:"source"`
+	LastRun     *time.Time `json:"last_run,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+	Inserted    int        `json:"inserted"`
+	Updated     int        `json:"updated"`
+	Duplicates  int        `json:"duplicates"`
+}
+
+type SyncStatus struct {
+	Running  bool           `json:"running"`
+	Interval string         `json:"interval,omitempty"`
+	NextRun  *time.Time     `json:"next_run,omitempty"`
+	Sources  []SourceStatus `json:"sources"`
+}
+
+// SyncService refreshes the store from external sources, once on demand or on a
+// schedule in the background, and remembers how the last run of each source went.
+type SyncService struct {
+	sourceService *SourceService
+	sources       []repositories.QuoteSource
+	interval      time.Duration
+
+	mu       sync.Mutex
+	statuses []SourceStatus
+	nextRun  *time.Time
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func NewSyncService(sourceService *SourceService, sources []repositories.QuoteSource, interval time.Duration) *SyncService {
+	statuses := make([]SourceStatus, len(sources))
+	for i, source := range sources {
+		statuses[i].Source = source.Name()
+	}
+
+	return &SyncService{
+		sourceService: sourceService,
+		sources:       sources,
+		interval:      interval,
+		statuses:      statuses,
+	}
+}
+
+// SyncNow fetches every source once. A failing source is recorded and skipped.
+func (ss *SyncService) SyncNow(ctx context.Context) {
+	for i, source := range ss.sources {
+		if ctx.Err() != nil {
+			return
+		}
+
+		report, err := ss.sourceService.SeedDbFromSource(ctx, source)
+		now := time.Now()
+
+		ss.mu.Lock()
+		status := &ss.statuses[i]
+		status.LastRun = &now
+		if err != nil {
+			status.LastError = err.Error()
+			status.LastErrorAt = &now
+		} else {
+			status.LastSuccess = &now
+			status.Inserted = report.Inserted
+			status.Updated = report.Updated
+			status.Duplicates = report.Duplicates
+		}
+		ss.mu.Unlock()
+
+		if err != nil {
+			log.Printf("Error syncing quotes from %s: %s", source.Name(), err.Error())
+		}
+	}
+}
+
+// Start syncs every interval in the background until Stop is called. It does
+// nothing when the interval is not positive, there are no sources or it is already running.
+func (ss *SyncService) Start() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.interval <= 0 || len(ss.sources) == 0 || ss.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ss.cancel = cancel
+	ss.done = make(chan struct{})
+	ss.scheduleNext()
+
+	go ss.run(ctx, ss.done)
+}
+
+func (ss *SyncService) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(ss.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ss.SyncNow(ctx)
+
+			ss.mu.Lock()
+			ss.scheduleNext()
+			ss.mu.Unlock()
+		}
+	}
+}
+
+func (ss *SyncService) scheduleNext() {
+	next := time.Now().Add(ss.interval)
+	ss.nextRun = &next
+}
+
+// Stop cancels a sync in progress and waits for the background loop to exit.
+func (ss *SyncService) Stop() {
+	ss.mu.Lock()
+	cancel, done := ss.cancel, ss.done
+	ss.cancel = nil
+	ss.nextRun = nil
+	ss.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+func (ss *SyncService) Status() SyncStatus {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	status := SyncStatus{
+		Running: ss.cancel != nil,
+		Sources: make([]SourceStatus, len(ss.statuses)),
+	}
+	if ss.interval > 0 {
+		status.Interval = ss.interval.String()
+	}
+	if ss.nextRun != nil {
+		next := *ss.nextRun
+		status.NextRun = &next
+	}
+	copy(status.Sources, ss.statuses)
+
+	return status
+}
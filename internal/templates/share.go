@@ -0,0 +1,111 @@
+package templates
+
+import (
+	"embed"
+	"errors"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"strings"
+	texttemplate "text/template"
+)
+
+const (
+	shareSubjectFile = "share_subject.txt"
+	shareTextFile    = "share.txt"
+	shareHTMLFile    = "share.html"
+)
+
+//go:embed defaults
+var defaults embed.FS
+
+// ShareData is what the share email templates can use.
+type ShareData struct {
+	Quote      string
+	Author     string
+	Book       string
+	SenderName string
+	Message    string
+}
+
+// RenderedEmail is a share email ready to be sent.
+type RenderedEmail struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// ShareTemplates renders the share email: a subject and a plain-text body with
+// text/template, and an HTML body with html/template, which escapes the quote.
+type ShareTemplates struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// NewShareTemplates parses the share templates. A file of the same name in dir
+// (share_subject.txt, share.txt, share.html) replaces the embedded default;
+// the others keep it. An empty dir uses only the defaults.
+func NewShareTemplates(dir string) (*ShareTemplates, error) {
+	subject, err := readTemplate(dir, shareSubjectFile)
+	if err != nil {
+		return nil, err
+	}
+	text, err := readTemplate(dir, shareTextFile)
+	if err != nil {
+		return nil, err
+	}
+	html, err := readTemplate(dir, shareHTMLFile)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &ShareTemplates{}
+	if st.subject, err = texttemplate.New(shareSubjectFile).Parse(subject); err != nil {
+		return nil, err
+	}
+	if st.text, err = texttemplate.New(shareTextFile).Parse(text); err != nil {
+		return nil, err
+	}
+	if st.html, err = htmltemplate.New(shareHTMLFile).Parse(html); err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+func readTemplate(dir, name string) (string, error) {
+	if dir != "" {
+		content, err := fs.ReadFile(os.DirFS(dir), name)
+		if err == nil {
+			return string(content), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	content, err := defaults.ReadFile("defaults/" + name)
+	return string(content), err
+}
+
+func (st *ShareTemplates) Render(data ShareData) (RenderedEmail, error) {
+	var subject, text, html strings.Builder
+
+	if err := st.subject.Execute(&subject, data); err != nil {
+		return RenderedEmail{}, err
+	}
+	if err := st.text.Execute(&text, data); err != nil {
+		return RenderedEmail{}, err
+	}
+	if err := st.html.Execute(&html, data); err != nil {
+		return RenderedEmail{}, err
+	}
+
+	return RenderedEmail{
+		// A subject is one line; newlines left by the template would only be encoded.
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,186 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+func doJSON(t *testing.T, client *http.Client, method, url string, payload any) *http.Response {
+	var body []byte
+	if payload != nil {
+		body, _ = json.Marshal(payload)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { res.Body.Close() })
+
+	return res
+}
+
+func decodeJSON(res *http.Response, v any) error {
+	defer res.Body.Close()
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func Test_GetQuoteById_Success(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	res := doJSON(t, srv.Client(), http.MethodGet, srv.URL+"/quotes/76", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var quote models.Quote
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&quote))
+	require.Equal(t, "76", quote.Id)
+	require.Equal(t, "Albus Dumbledore", quote.Author)
+}
+
+func Test_GetQuoteById_404_if_missing(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	res := doJSON(t, srv.Client(), http.MethodGet, srv.URL+"/quotes/missing", nil)
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func Test_ReplaceQuote_Success(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	client := srv.Client()
+
+	res := doJSON(t, client, http.MethodPut, srv.URL+"/quotes/76", map[string]any{"text": "Replaced", "author": ""})
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var quote models.Quote
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&quote))
+	require.Equal(t, "76", quote.Id)
+	require.Equal(t, "Replaced", quote.Text)
+	require.Equal(t, "Unknown", quote.Author)
+
+	res = doJSON(t, client, http.MethodGet, srv.URL+"/quotes/76", nil)
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&quote))
+	require.Equal(t, "Replaced", quote.Text)
+}
+
+func Test_ReplaceQuote_Fails_400_with_EmptyText(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	res := doJSON(t, srv.Client(), http.MethodPut, srv.URL+"/quotes/76", map[string]any{"text": ""})
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func Test_CreateQuote_Fails_400_with_BlankText(t *testing.T) {
+	srv, _ := setupServer(false)
+	defer srv.Close()
+
+	res := doJSON(t, srv.Client(), http.MethodPost, srv.URL+"/add", map[string]any{"text": "   ", "author": "Someone"})
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func Test_ReplaceQuote_Fails_400_with_BlankText(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	res := doJSON(t, srv.Client(), http.MethodPut, srv.URL+"/quotes/76", map[string]any{"text": "   "})
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func Test_ReplaceQuote_404_if_missing(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	res := doJSON(t, srv.Client(), http.MethodPut, srv.URL+"/quotes/missing", map[string]any{"text": "Text"})
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func Test_PatchQuote_Changes_Only_Given_Fields(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	res := doJSON(t, srv.Client(), http.MethodPatch, srv.URL+"/quotes/97", map[string]any{"author": "Laozi"})
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var quote models.Quote
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&quote))
+	require.Equal(t, "Laozi", quote.Author)
+	require.Contains(t, quote.Text, "At the center of your being")
+}
+
+func Test_PatchQuote_Fails_400_with_BlankText(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	res := doJSON(t, srv.Client(), http.MethodPatch, srv.URL+"/quotes/97", map[string]any{"text": "   "})
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func Test_DeleteQuote_Success(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	client := srv.Client()
+
+	res := doJSON(t, client, http.MethodDelete, srv.URL+"/quotes/76", nil)
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	res = doJSON(t, client, http.MethodGet, srv.URL+"/quotes/76", nil)
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	res = doJSON(t, client, http.MethodDelete, srv.URL+"/quotes/76", nil)
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func Test_ListQuotes_Paginates_And_Sorts(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	client := srv.Client()
+
+	res := doJSON(t, client, http.MethodGet, srv.URL+"/quotes?limit=1&page=2", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var page services.QuotePage
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&page))
+	require.Equal(t, 2, page.Total)
+	require.Equal(t, 2, page.Page)
+	require.Len(t, page.Quotes, 1)
+	require.Equal(t, "97", page.Quotes[0].Id)
+
+	res = doJSON(t, client, http.MethodGet, srv.URL+"/quotes?sort=author&order=desc", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&page))
+	require.Len(t, page.Quotes, 2)
+	require.Equal(t, "Lao Tzu", page.Quotes[0].Author)
+	require.Equal(t, "Albus Dumbledore", page.Quotes[1].Author)
+
+	res = doJSON(t, client, http.MethodGet, srv.URL+"/quotes?page=5", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&page))
+	require.Empty(t, page.Quotes)
+}
+
+func Test_ListQuotes_Fails_400_on_InvalidParams(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	client := srv.Client()
+
+	for _, query := range []string{"page=0", "limit=1000", "limit=abc", "sort=text", "order=up"} {
+		res := doJSON(t, client, http.MethodGet, srv.URL+"/quotes?"+query, nil)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+	}
+}
@@ -0,0 +1,184 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/search"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+func Test_Fingerprint_Ignores_Case_Punctuation_And_Whitespace(t *testing.T) {
+	require.Equal(t, search.Fingerprint("Stay hungry, stay foolish."), search.Fingerprint("  stay HUNGRY stay   foolish!"))
+	require.Equal(t, search.Fingerprint("Été"), search.Fingerprint("ete"))
+	require.NotEqual(t, search.Fingerprint("Stay hungry, stay foolish."), search.Fingerprint("Stay hungry."))
+}
+
+func Test_Similarity(t *testing.T) {
+	require.InDelta(t, 1.0, search.Similarity("Stay hungry, stay foolish.", "stay hungry stay foolish"), 0.001)
+	require.Greater(t, search.Similarity("It always seems impossible until it's done.", "It always seems impossible until it is done"), 0.9)
+	require.Less(t, search.Similarity("Stay hungry, stay foolish.", "Stay humble, stay kind."), 0.5)
+}
+
+func Test_CreateNewQuote_Fails_409_on_Duplicate(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	client := srv.Client()
+
+	texts := []string{
+		"it's the UNKNOWN we fear, when we look upon death and darkness -- nothing more!",
+		"It is the unknown we fear when we look upon death and darkness, nothing more.",
+	}
+	for _, text := range texts {
+		res := doJSON(t, client, http.MethodPost, srv.URL+"/add", map[string]any{"text": text, "author": "Someone"})
+		require.Equal(t, http.StatusConflict, res.StatusCode, text)
+	}
+
+	res := doJSON(t, client, http.MethodPost, srv.URL+"/add", map[string]any{"text": "An entirely new quote."})
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+}
+
+func Test_CreateQuote_Concurrent_Duplicates_Store_One(t *testing.T) {
+	repo := newSqliteRepository(t, filepath.Join(t.TempDir(), "quotes.db"))
+	service := services.NewQuoteService(repo)
+
+	for round := range 20 {
+		// Distinct texts, so rounds are not near-duplicates of each other.
+		text := uuid.New().String()
+		start := make(chan struct{})
+		var created atomic.Int32
+		var wg sync.WaitGroup
+
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				_, err := service.CreateQuote(text, "Author", nil, "")
+				if err == nil {
+					created.Add(1)
+					return
+				}
+				assert.ErrorIs(t, err, errs.ErrAlreadyExists)
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		require.Equal(t, int32(1), created.Load(), "round %d", round)
+	}
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 20)
+}
+
+func Test_PatchQuote_Fails_409_when_Text_Duplicates_Another(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	client := srv.Client()
+
+	payload := map[string]any{"text": "It's the unknown we fear when we look upon death and darkness, nothing more."}
+	res := doJSON(t, client, http.MethodPatch, srv.URL+"/quotes/97", payload)
+	require.Equal(t, http.StatusConflict, res.StatusCode)
+
+	// Re-saving a quote's own text is not a conflict.
+	res = doJSON(t, client, http.MethodPatch, srv.URL+"/quotes/76", payload)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func Test_SeedDbFromFile_Skips_Duplicates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seed.json")
+	err := os.WriteFile(path, []byte(`[
+		{"id": "1", "text": "Stay hungry, stay foolish.", "author": "Steve Jobs"},
+		{"id": "2", "text": "stay hungry stay foolish", "author": "Steve Jobs"},
+		{"id": "3", "text": "Simplicity is the ultimate sophistication.", "author": "Leonardo da Vinci"}
+	]`), 0o644)
+	require.NoError(t, err)
+
+	repo := repositories.NewInMemoryQuoteRepository()
+	service := services.NewQuoteService(repo)
+
+	report, err := service.SeedDbFromFile(path, services.SeedOptions{})
+	require.NoError(t, err)
+	require.Equal(t, &services.ImportReport{BatchId: report.BatchId, Inserted: 2, Duplicates: 1}, report)
+
+	report, err = service.SeedDbFromFile(path, services.SeedOptions{})
+	require.NoError(t, err)
+	require.Equal(t, &services.ImportReport{BatchId: report.BatchId, Inserted: 0, Duplicates: 3}, report)
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 2)
+}
+
+func Test_SeedDbFromFile_Does_Not_Overwrite_On_Id_Conflict(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+	_, err := repo.Save(models.Quote{Id: "1", Text: "Already stored.", Author: "Someone"})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "seed.json")
+	err = os.WriteFile(path, []byte(`[
+		{"id": "1", "text": "Stay hungry, stay foolish.", "author": "Steve Jobs"},
+		{"id": "2", "text": "Simplicity is the ultimate sophistication.", "author": "Leonardo da Vinci"},
+		{"id": "2", "text": "Well done is better than well said.", "author": "Benjamin Franklin"}
+	]`), 0o644)
+	require.NoError(t, err)
+
+	for _, dryRun := range []bool{true, false} {
+		report, err := services.NewQuoteService(repo).SeedDbFromFile(path, services.SeedOptions{DryRun: dryRun})
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Inserted)
+		require.Equal(t, 2, report.Conflicts)
+		require.Zero(t, report.Invalid)
+		require.Equal(t, []services.RecordProblem{
+			{Record: 1, Reason: `id "1" is already used by another quote`},
+			{Record: 3, Reason: `id "2" is already used by another quote`},
+		}, report.Problems)
+	}
+
+	quote, err := repo.Find("1")
+	require.NoError(t, err)
+	require.Equal(t, "Already stored.", quote.Text)
+	quote, err = repo.Find("2")
+	require.NoError(t, err)
+	require.Equal(t, "Simplicity is the ultimate sophistication.", quote.Text)
+}
+
+func Test_SeedDbFromSource_Skips_Duplicates(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"q": "Well done is better than well said.", "a": "Benjamin Franklin"},
+			{"q": "The journey of a thousand miles begins with one step.", "a": "Lao Tzu"}
+		]`))
+	}))
+	defer api.Close()
+
+	repo := repositories.NewInMemoryQuoteRepository()
+	service := services.NewSourceService(repo)
+	source := repositories.NewZenQuoteRepository(api.URL, nil)
+
+	report, err := service.SeedDbFromSource(context.Background(), source)
+	require.NoError(t, err)
+	require.Equal(t, &services.ImportReport{BatchId: report.BatchId, Inserted: 2, Duplicates: 0}, report)
+
+	report, err = service.SeedDbFromSource(context.Background(), source)
+	require.NoError(t, err)
+	require.Equal(t, &services.ImportReport{BatchId: report.BatchId, Inserted: 0, Duplicates: 2}, report)
+}
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/formats"
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+var exportedQuotes = []models.Quote{
+	{Id: "1", Text: "Stay hungry, stay foolish.", Author: "Steve Jobs", Tags: []string{"life", "work"}, Language: "en",
+		Source: "zenquotes", ExternalId: "z1", BatchId: "b1", CreatedBy: "system",
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	{Id: "2", Text: "He said \"no\"; then left.\nTwice.", Author: "Unknown", Tags: []string{"humor"}, Source: models.SourceApi, CreatedBy: "dashboard",
+		CreatedAt: time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC), UpdatedAt: time.Date(2024, 6, 7, 8, 9, 10, 0, time.UTC)},
+}
+
+func Test_Export_RoundTrips_Every_Format(t *testing.T) {
+	srv := setupServerWithQuotes(t, exportedQuotes...)
+	client := srv.Client()
+
+	for _, format := range []string{"json", "ndjson", "csv", "yaml"} {
+		res, err := client.Get(srv.URL + "/quotes/export?format=" + format)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode, format)
+		require.Contains(t, res.Header.Get("Content-Disposition"), "quotes."+format)
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		require.NoError(t, err)
+
+		reader, err := formats.NewReader(bytes.NewReader(body), "export."+format)
+		require.NoError(t, err, format)
+		quotes, failed := readAll(t, reader)
+		require.Empty(t, failed, format)
+		require.Len(t, quotes, 2, format)
+
+		// Provenance survives too, so a backup can be told apart from a fresh import.
+		require.Equal(t, exportedQuotes, quotes, format)
+
+		// Seeding the export into empty storage restores the collection.
+		path := filepath.Join(t.TempDir(), "backup."+format)
+		require.NoError(t, os.WriteFile(path, body, 0o644))
+
+		repo := repositories.NewInMemoryQuoteRepository()
+		report, err := services.NewQuoteService(repo).SeedDbFromFile(path, services.SeedOptions{})
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Inserted, format)
+		require.Zero(t, report.Invalid, format)
+
+		restored, err := repo.List()
+		require.NoError(t, err)
+		require.Equal(t, exportedQuotes, restored, format)
+	}
+}
+
+func Test_Export_Empty_Collection_Is_Readable(t *testing.T) {
+	srv := setupServerWithQuotes(t)
+
+	for _, format := range []string{"json", "ndjson", "csv", "yaml"} {
+		res, err := srv.Client().Get(srv.URL + "/quotes/export?format=" + format)
+		require.NoError(t, err)
+
+		reader, err := formats.NewReader(res.Body, "export."+format)
+		require.NoError(t, err, format)
+		quotes, _ := readAll(t, reader)
+		require.Empty(t, quotes, format)
+		res.Body.Close()
+	}
+}
+
+func Test_Export_Gzip_And_Filters(t *testing.T) {
+	srv := setupServerWithQuotes(t, exportedQuotes...)
+
+	res, err := srv.Client().Get(srv.URL + "/quotes/export?format=ndjson&gzip=true&source=zenquotes")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, "application/gzip", res.Header.Get("Content-Type"))
+	require.Contains(t, res.Header.Get("Content-Disposition"), "quotes.ndjson.gz")
+
+	gz, err := gzip.NewReader(res.Body)
+	require.NoError(t, err)
+	reader, err := formats.NewReader(gz, "export.ndjson")
+	require.NoError(t, err)
+
+	quotes, _ := readAll(t, reader)
+	require.Equal(t, []models.Quote{exportedQuotes[0]}, quotes)
+}
+
+func Test_Export_Rejects_Unknown_Format(t *testing.T) {
+	srv := setupServerWithQuotes(t)
+
+	res, err := srv.Client().Get(srv.URL + "/quotes/export?format=xml")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
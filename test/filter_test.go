@@ -0,0 +1,87 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+var filterQuotes = []models.Quote{
+	{Id: "1", Text: "Luck is what happens when preparation meets opportunity.", Author: "Lucius Annaeus Seneca", Tags: []string{"luck"}, Language: "en"},
+	{Id: "2", Text: "We suffer more in imagination.", Author: "Seneca", Tags: []string{"stoicism"}, Language: "en"},
+	{Id: "3", Text: "Wer kämpft, kann verlieren. Wer nicht kämpft, hat schon verloren.", Author: "Bertolt Brecht", Tags: []string{"courage"}, Language: "de"},
+}
+
+func getFilteredQuote(t *testing.T, client *http.Client, url string) (*http.Response, models.Quote) {
+	res := doJSON(t, client, http.MethodGet, url, nil)
+
+	var quote models.Quote
+	if res.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&quote))
+	}
+
+	return res, quote
+}
+
+func Test_GetRandomQuote_Filters(t *testing.T) {
+	srv := setupServerWithQuotes(t, filterQuotes...)
+	client := srv.Client()
+
+	cases := map[string]string{
+		"/quote?author=seneca&max_length=40": "2",
+		"/quote?tag=LUCK":                    "1",
+		"/quote?lang=de":                     "3",
+		"/quote?min_length=60":               "3",
+		"/quote?author=seneca&exclude=2":     "1",
+		"/quote?exclude=1,2":                 "3",
+		"/quote?exclude=1&exclude=3":         "2",
+	}
+
+	for query, expectedId := range cases {
+		// Only one quote matches each query, so repeated draws must always return it.
+		for range 5 {
+			res, quote := getFilteredQuote(t, client, srv.URL+query)
+			require.Equal(t, http.StatusOK, res.StatusCode, query)
+			require.Equal(t, expectedId, quote.Id, query)
+		}
+	}
+}
+
+func Test_GetRandomQuote_404_when_nothing_matches(t *testing.T) {
+	srv := setupServerWithQuotes(t, filterQuotes...)
+
+	res, _ := getFilteredQuote(t, srv.Client(), srv.URL+"/quote?author=seneca&lang=de")
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func Test_GetRandomQuote_400_on_invalid_filters(t *testing.T) {
+	srv := setupServerWithQuotes(t, filterQuotes...)
+	client := srv.Client()
+
+	for _, query := range []string{"min_length=abc", "max_length=-1", "min_length=50&max_length=10"} {
+		res, _ := getFilteredQuote(t, client, srv.URL+"/quote?"+query)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+	}
+}
+
+func Test_SeedDbFromFile_Keeps_Language(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seed.json")
+	seed := `[{"id": "1", "text": "Carpe diem.", "author": "Horace", "language": "la"}]`
+	require.NoError(t, os.WriteFile(path, []byte(seed), 0o644))
+
+	service := services.NewQuoteService(repositories.NewInMemoryQuoteRepository())
+	_, err := service.SeedDbFromFile(path, services.SeedOptions{})
+	require.NoError(t, err)
+
+	quote, err := service.GetRandomQuote(services.QuoteFilter{Language: "LA"})
+	require.NoError(t, err)
+	require.Equal(t, "1", quote.Id)
+}
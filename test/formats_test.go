@@ -0,0 +1,164 @@
+package test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/formats"
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+// readAll collects every quote from reader, and the records that failed to decode.
+func readAll(t *testing.T, reader formats.QuoteReader) ([]models.Quote, []int) {
+	t.Helper()
+
+	quotes := []models.Quote{}
+	failed := []int{}
+	for {
+		quote, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return quotes, failed
+		}
+		var recordErr *formats.RecordError
+		if errors.As(err, &recordErr) {
+			failed = append(failed, recordErr.Record)
+			continue
+		}
+		require.NoError(t, err)
+		quotes = append(quotes, quote)
+	}
+}
+
+func gzipBytes(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+const csvQuotes = "\ufeffQuote,By,Year,Categories\n" +
+	"\"Stay hungry, stay foolish.\",Steve Jobs,2005,\"life; work\"\n" +
+	"Simplicity is the ultimate sophistication.,Leonardo da Vinci,,\n"
+
+func Test_Formats_CSV_Maps_Header(t *testing.T) {
+	reader, err := formats.NewReader(strings.NewReader(csvQuotes), "quotes.csv")
+	require.NoError(t, err)
+
+	quotes, failed := readAll(t, reader)
+	require.Empty(t, failed)
+	require.Equal(t, []models.Quote{
+		{Text: "Stay hungry, stay foolish.", Author: "Steve Jobs", Tags: []string{"life", "work"}},
+		{Text: "Simplicity is the ultimate sophistication.", Author: "Leonardo da Vinci"},
+	}, quotes)
+}
+
+func Test_Formats_CSV_Requires_Text_Column(t *testing.T) {
+	_, err := formats.NewReader(strings.NewReader("author,year\nSomeone,2000\n"), "quotes.csv")
+	require.Error(t, err)
+}
+
+func Test_Formats_CSV_Reports_Bad_Timestamps(t *testing.T) {
+	data := "text,created_at\nGood.,2024-01-02T03:04:05Z\nBad.,yesterday\n"
+	reader, err := formats.NewReader(strings.NewReader(data), "quotes.csv")
+	require.NoError(t, err)
+
+	quotes, failed := readAll(t, reader)
+	require.Equal(t, []int{2}, failed)
+	require.Len(t, quotes, 1)
+	require.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), quotes[0].CreatedAt)
+}
+
+func Test_Formats_NDJSON_Skips_Blank_And_Reports_Bad_Lines(t *testing.T) {
+	data := `{"id": "1", "text": "One", "author": "A"}
+
+not json
+{"id": "3", "text": "Three", "author": "C", "tags": ["x"]}
+`
+	reader, err := formats.NewReader(strings.NewReader(data), "quotes.jsonl")
+	require.NoError(t, err)
+
+	quotes, failed := readAll(t, reader)
+	require.Equal(t, []int{2}, failed)
+	require.Len(t, quotes, 2)
+	require.Equal(t, "Three", quotes[1].Text)
+	require.Equal(t, []string{"x"}, quotes[1].Tags)
+}
+
+func Test_Formats_JSON_Continues_Past_Wrong_Types(t *testing.T) {
+	data := `[{"text": "One", "author": "A"}, {"text": 2}, {"text": "Three", "author": "C"}]`
+	reader, err := formats.NewReader(strings.NewReader(data), "quotes.json")
+	require.NoError(t, err)
+
+	quotes, failed := readAll(t, reader)
+	require.Equal(t, []int{2}, failed)
+	require.Len(t, quotes, 2)
+}
+
+func Test_Formats_YAML(t *testing.T) {
+	data := `
+- id: "1"
+  text: Well begun is half done.
+  author: Aristotle
+  tags: [beginnings]
+- text: Know thyself.
+  author: Socrates
+  language: en
+`
+	reader, err := formats.NewReader(strings.NewReader(data), "quotes.yml")
+	require.NoError(t, err)
+
+	quotes, _ := readAll(t, reader)
+	require.Equal(t, []models.Quote{
+		{Id: "1", Text: "Well begun is half done.", Author: "Aristotle", Tags: []string{"beginnings"}},
+		{Text: "Know thyself.", Author: "Socrates", Language: "en"},
+	}, quotes)
+}
+
+func Test_Formats_Sniffs_Content_And_Gzip(t *testing.T) {
+	cases := map[string]string{
+		"json":   `[{"text": "Sniffed.", "author": "Nose"}]`,
+		"ndjson": `{"text": "Sniffed.", "author": "Nose"}`,
+		"yaml":   "- text: Sniffed.\n  author: Nose\n",
+		"csv":    "text,author\nSniffed.,Nose\n",
+	}
+
+	for name, data := range cases {
+		for _, input := range [][]byte{[]byte(data), gzipBytes(t, data)} {
+			reader, err := formats.NewReader(bytes.NewReader(input), "upload")
+			require.NoError(t, err, name)
+
+			quotes, failed := readAll(t, reader)
+			require.Empty(t, failed, name)
+			require.Equal(t, []models.Quote{{Text: "Sniffed.", Author: "Nose"}}, quotes, name)
+		}
+	}
+}
+
+func Test_SeedDbFromFile_Gzipped_CSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.csv.gz")
+	require.NoError(t, os.WriteFile(path, gzipBytes(t, csvQuotes), 0o644))
+
+	repo := repositories.NewInMemoryQuoteRepository()
+	report, err := services.NewQuoteService(repo).SeedDbFromFile(path, services.SeedOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Inserted)
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 2)
+	require.Equal(t, []string{"life", "work"}, quotes[0].Tags)
+	require.Equal(t, models.SourceFile, quotes[0].Source)
+}
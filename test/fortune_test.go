@@ -0,0 +1,118 @@
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/formats"
+	"github.com/danilobml/motivate/internal/models"
+)
+
+const fortuneFile = "A day for firm decisions!!!!!  Or is it?\n" +
+	"%\n" +
+	"Whenever you find yourself on the side of the majority,\n" +
+	"it is time to pause and reflect.\n" +
+	"\t\t-- Mark Twain\n" +
+	"\n" +
+	"%\n" +
+	"%\n" +
+	"-- A line that only looks like an author\n" +
+	"%%\r\n" +
+	"Windows line endings.\r\n" +
+	"\t\t— Someone\r\n" +
+	"%\n"
+
+func Test_Fortune_Reader(t *testing.T) {
+	for _, name := range []string{"wisdom.fortune", "wisdom"} {
+		reader, err := formats.NewReader(strings.NewReader(fortuneFile), name)
+		require.NoError(t, err, name)
+
+		quotes, failed := readAll(t, reader)
+		require.Empty(t, failed)
+		require.Equal(t, []models.Quote{
+			{Text: "A day for firm decisions!!!!!  Or is it?"},
+			{Text: "Whenever you find yourself on the side of the majority,\nit is time to pause and reflect.", Author: "Mark Twain"},
+			{Text: "-- A line that only looks like an author"},
+			{Text: "Windows line endings.", Author: "Someone"},
+		}, quotes, name)
+	}
+}
+
+// strfileHeader is the layout written by strfile(8) at the start of a .dat file.
+type strfileHeader struct {
+	Version  uint32
+	Count    uint32
+	Longest  uint32
+	Shortest uint32
+	Flags    uint32
+	Stuff    [4]byte
+}
+
+func Test_Fortune_Writer_And_Strfile_Index(t *testing.T) {
+	quotes := []models.Quote{
+		{Text: "Short.", Author: "Unknown"},
+		{Text: "Two lines\nof wisdom.", Author: "Sage"},
+		{Text: "Percent\n%\ninside.", Author: "Tricky"},
+	}
+
+	var fortune, index bytes.Buffer
+	writer := formats.NewFortuneWriter(&fortune)
+	for _, quote := range quotes {
+		require.NoError(t, writer.Write(quote))
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, writer.WriteIndex(&index))
+
+	var header strfileHeader
+	require.NoError(t, binary.Read(&index, binary.BigEndian, &header))
+	require.Equal(t, uint32(2), header.Version)
+	require.Equal(t, uint32(3), header.Count)
+	require.Equal(t, byte('%'), header.Stuff[0])
+	require.Equal(t, uint32(len("Short.\n")), header.Shortest)
+
+	offsets := make([]uint32, header.Count+1)
+	require.NoError(t, binary.Read(&index, binary.BigEndian, offsets))
+	_, err := index.ReadByte()
+	require.ErrorIs(t, err, io.EOF)
+
+	data := fortune.Bytes()
+	require.Equal(t, uint32(len(data)), offsets[header.Count])
+	require.Equal(t, "Short.\n%\n", string(data[offsets[0]:offsets[1]]))
+	require.Equal(t, "Two lines\nof wisdom.\n\t\t-- Sage\n%\n", string(data[offsets[1]:offsets[2]]))
+
+	longest := uint32(0)
+	for i := range header.Count {
+		longest = max(longest, offsets[i+1]-offsets[i]-2)
+	}
+	require.Equal(t, longest, header.Longest)
+
+	reader, err := formats.NewReader(bytes.NewReader(data), "out.fortune")
+	require.NoError(t, err)
+	read, _ := readAll(t, reader)
+	require.Len(t, read, 3)
+	require.Equal(t, "Short.", read[0].Text)
+	require.Empty(t, read[0].Author)
+	require.Equal(t, "Sage", read[1].Author)
+	require.Equal(t, "Percent\n %\ninside.", read[2].Text)
+	require.Equal(t, "Tricky", read[2].Author)
+}
+
+func Test_Export_Fortune(t *testing.T) {
+	srv := setupServerWithQuotes(t, models.Quote{Id: "1", Text: "Know thyself.", Author: "Socrates"})
+
+	res, err := srv.Client().Get(srv.URL + "/quotes/export?format=fortune")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "text/plain; charset=utf-8", res.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "Know thyself.\n\t\t-- Socrates\n%\n", string(body))
+}
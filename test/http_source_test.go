@@ -0,0 +1,110 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/repositories"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Source_RateLimited_With_RetryAfter(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("<html>Too many requests</html>"))
+	}))
+	defer api.Close()
+
+	_, err := repositories.NewZenQuoteRepository(api.URL, nil).Fetch(context.Background())
+	require.ErrorIs(t, err, errs.ErrRateLimited)
+
+	var sourceErr *repositories.SourceError
+	require.ErrorAs(t, err, &sourceErr)
+	require.Equal(t, "zenquotes", sourceErr.Source)
+	require.Equal(t, http.StatusTooManyRequests, sourceErr.StatusCode)
+	require.Equal(t, 30*time.Second, sourceErr.RetryAfter)
+}
+
+func Test_ZenQuotes_Placeholder_Quote_Is_RateLimited(t *testing.T) {
+	api := newJSONServer(t, `[{"q": "Too many requests. Obtain an auth key for unlimited access.", "a": "zenquotes.io"}]`)
+
+	_, err := repositories.NewZenQuoteRepository(api.URL, nil).Fetch(context.Background())
+	require.ErrorIs(t, err, errs.ErrRateLimited)
+}
+
+func Test_Source_Rejects_Bad_Responses(t *testing.T) {
+	cases := map[string]http.HandlerFunc{
+		"server error": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+		"html body": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<html></html>"))
+		},
+		"oversized body": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"q": "` + strings.Repeat("a", 6<<20) + `", "a": "x"}]`))
+		},
+	}
+
+	for name, handler := range cases {
+		api := httptest.NewServer(handler)
+
+		_, err := repositories.NewDummyJsonRepository(api.URL, nil).Fetch(context.Background())
+		require.ErrorIs(t, err, errs.ErrBadUpstreamResponse, name)
+		require.NotErrorIs(t, err, errs.ErrRateLimited, name)
+
+		api.Close()
+	}
+}
+
+func Test_Source_Network_Failure(t *testing.T) {
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})}
+
+	_, err := repositories.NewQuotableRepository("http://quotes.invalid", client).Fetch(context.Background())
+	require.ErrorIs(t, err, errs.ErrNetwork)
+	require.Contains(t, err.Error(), "connection refused")
+}
+
+func Test_Source_Uses_Injected_Client_And_Timeout(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer api.Close()
+
+	client := &http.Client{Timeout: 5 * time.Millisecond}
+
+	_, err := repositories.NewZenQuoteRepository(api.URL, client).Fetch(context.Background())
+	require.ErrorIs(t, err, errs.ErrNetwork)
+}
+
+func Test_Source_Honours_Context(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer api.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := repositories.NewZenQuoteRepository(api.URL, nil).Fetch(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
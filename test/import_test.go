@@ -0,0 +1,261 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/formats"
+	"github.com/danilobml/motivate/internal/handlers"
+	"github.com/danilobml/motivate/internal/mocks"
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+const kindleClippings = "\ufeffMeditations (Marcus Aurelius)\r\n" +
+	"- Your Highlight on page 12 | Location 170-172 | Added on Monday, 3 March 2025 08:12:01\r\n" +
+	"\r\n" +
+	"The impediment to action advances action.\r\n" +
+	"==========\r\n" +
+	"Meditations (Marcus Aurelius)\r\n" +
+	"- Your Note on page 12 | Location 172 | Added on Monday, 3 March 2025 08:13:10\r\n" +
+	"\r\n" +
+	"Remember this one.\r\n" +
+	"==========\r\n" +
+	"Meditations (Marcus Aurelius)\r\n" +
+	"- Your Bookmark on page 40 | Location 600 | Added on Monday, 3 March 2025 09:00:00\r\n" +
+	"\r\n" +
+	"\r\n" +
+	"==========\r\n" +
+	"The Obstacle Is the Way (Penguin Edition) (Ryan Holiday)\r\n" +
+	"- Your Highlight at location 88-89 | Added on Tuesday, 4 March 2025 21:40:00\r\n" +
+	"\r\n" +
+	"What stands in the way\r\n" +
+	"==========\r\n" +
+	"Meditations (Marcus Aurelius)\r\n" +
+	"- Your Highlight on page 12 | Location 170-173 | Added on Monday, 3 March 2025 08:14:00\r\n" +
+	"\r\n" +
+	"The impediment to action advances action. What stands in the way becomes the way.\r\n" +
+	"==========\r\n" +
+	"The Obstacle Is the Way (Penguin Edition) (Ryan Holiday)\r\n" +
+	"- Your Highlight at location 88-90 | Added on Tuesday, 4 March 2025 21:41:00\r\n" +
+	"\r\n" +
+	"What stands in the way becomes the way.\r\n" +
+	"==========\r\n" +
+	"Untitled Notes\r\n" +
+	"- Your Highlight at location 5 | Added on Wednesday, 5 March 2025 07:00:00\r\n" +
+	"\r\n" +
+	"<You have reached the clipping limit for this item>\r\n" +
+	"==========\r\n"
+
+const goodreadsQuotes = "Quote,Author,Book,Tags,Likes\n" +
+	"\"“Be the change that you wish to see in the world.” ― Mahatma Gandhi\",\"Mahatma Gandhi,\",,\"action, change\",120\n" +
+	"\"“So many books, so little time.”\",Frank Zappa,,books,80\n"
+
+func Test_Kindle_Clippings(t *testing.T) {
+	reader, err := formats.NewReader(strings.NewReader(kindleClippings), "My Clippings.txt")
+	require.NoError(t, err)
+
+	quotes, failed := readAll(t, reader)
+	require.Empty(t, failed)
+	require.Equal(t, []models.Quote{
+		{
+			Text:       "The impediment to action advances action. What stands in the way becomes the way.",
+			Author:     "Marcus Aurelius",
+			Book:       "Meditations",
+			Source:     models.SourceKindle,
+			ExternalId: "170-173",
+		},
+		{
+			Text:       "What stands in the way becomes the way.",
+			Author:     "Ryan Holiday",
+			Book:       "The Obstacle Is the Way (Penguin Edition)",
+			Source:     models.SourceKindle,
+			ExternalId: "88-90",
+		},
+	}, quotes)
+
+	reader, err = formats.NewReader(strings.NewReader(kindleClippings), "highlights")
+	require.NoError(t, err)
+	sniffed, _ := readAll(t, reader)
+	require.Equal(t, quotes, sniffed)
+}
+
+func Test_Goodreads_Quotes(t *testing.T) {
+	reader, err := formats.NewReader(strings.NewReader(goodreadsQuotes), "goodreads_quotes.csv")
+	require.NoError(t, err)
+
+	quotes, failed := readAll(t, reader)
+	require.Empty(t, failed)
+	require.Equal(t, []models.Quote{
+		{Text: "Be the change that you wish to see in the world.", Author: "Mahatma Gandhi", Tags: []string{"action", "change"}, Source: models.SourceGoodreads},
+		{Text: "So many books, so little time.", Author: "Frank Zappa", Tags: []string{"books"}, Source: models.SourceGoodreads},
+	}, quotes)
+}
+
+func Test_SeedDbFromFile_Kindle_With_Format(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.txt")
+	require.NoError(t, os.WriteFile(path, []byte(kindleClippings), 0o644))
+
+	repo := repositories.NewInMemoryQuoteRepository()
+	report, err := services.NewQuoteService(repo).SeedDbFromFile(path, services.SeedOptions{Format: formats.Kindle})
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Inserted)
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Equal(t, models.SourceKindle, quotes[0].Source)
+	require.Equal(t, "Meditations", quotes[0].Book)
+	require.Equal(t, "170-173", quotes[0].ExternalId)
+	require.Equal(t, report.BatchId, quotes[0].BatchId)
+}
+
+func setupImportServer(t *testing.T, concurrency int) *httptest.Server {
+	service := services.NewQuoteService(repositories.NewInMemoryQuoteRepository())
+	jobService := services.NewImportJobService(service, concurrency, time.Hour)
+	t.Cleanup(jobService.Stop)
+
+	router := handlers.NewQuotesRouter(service, &mocks.MockMailer{}, defaultShareTemplates())
+	srv := httptest.NewTLSServer(handlers.RegisterRoutes(router, handlers.NewImportRouter(jobService), nil))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func postImport(t *testing.T, client *http.Client, url, contentType string, body io.Reader) services.ImportJob {
+	res, err := client.Post(url, contentType, body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, res.StatusCode)
+
+	var job services.ImportJob
+	require.NoError(t, decodeJSON(res, &job))
+	require.Equal(t, "/jobs/"+job.Id, res.Header.Get("Location"))
+
+	return job
+}
+
+func waitForJob(t *testing.T, client *http.Client, baseUrl, id string) services.ImportJob {
+	var job services.ImportJob
+	require.Eventually(t, func() bool {
+		res, err := client.Get(baseUrl + "/jobs/" + id)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, decodeJSON(res, &job))
+		return job.Finished()
+	}, 10*time.Second, 10*time.Millisecond)
+
+	return job
+}
+
+func Test_ImportQuotes_Multipart_Upload(t *testing.T) {
+	srv := setupImportServer(t, 1)
+	client := srv.Client()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "My Clippings.txt")
+	require.NoError(t, err)
+	part.Write([]byte(kindleClippings))
+	require.NoError(t, form.Close())
+
+	job := postImport(t, client, srv.URL+"/quotes/import", form.FormDataContentType(), &body)
+	require.Equal(t, "My Clippings.txt", job.FileName)
+
+	job = waitForJob(t, client, srv.URL, job.Id)
+	require.Equal(t, services.JobCompleted, job.State)
+	require.Equal(t, 2, job.Processed)
+	require.Equal(t, 2, job.Inserted)
+	require.NotEmpty(t, job.BatchId)
+	require.False(t, job.FinishedAt.IsZero())
+
+	res, err := client.Get(srv.URL + "/quotes?source=kindle&batch_id=" + job.BatchId)
+	require.NoError(t, err)
+	var page services.QuotePage
+	require.NoError(t, decodeJSON(res, &page))
+	require.Len(t, page.Quotes, 2)
+	require.Equal(t, "Meditations", page.Quotes[0].Book)
+}
+
+func Test_ImportQuotes_Raw_Body(t *testing.T) {
+	srv := setupImportServer(t, 1)
+	client := srv.Client()
+
+	job := postImport(t, client, srv.URL+"/quotes/import?format=goodreads&dry_run=true", "text/csv", strings.NewReader(goodreadsQuotes))
+	job = waitForJob(t, client, srv.URL, job.Id)
+	require.True(t, job.DryRun)
+	require.Equal(t, 2, job.Inserted)
+
+	ndjson := "{\"text\": \"Uploaded.\"}\n{\"text\": \"uploaded\"}\n{\"text\": \"\"}\n"
+	job = postImport(t, client, srv.URL+"/quotes/import", "application/x-ndjson", strings.NewReader(ndjson))
+	job = waitForJob(t, client, srv.URL, job.Id)
+	require.Equal(t, services.JobCompleted, job.State)
+	require.Equal(t, 3, job.Processed)
+	require.Equal(t, 1, job.Inserted)
+	require.Equal(t, 1, job.Skipped)
+	require.Equal(t, 1, job.Errors)
+	require.Equal(t, 3, job.Problems[0].Record)
+
+	res, err := client.Get(srv.URL + "/quotes?source=upload")
+	require.NoError(t, err)
+	var page services.QuotePage
+	require.NoError(t, decodeJSON(res, &page))
+	require.Len(t, page.Quotes, 1)
+	require.Equal(t, "Uploaded.", page.Quotes[0].Text)
+
+	job = postImport(t, client, srv.URL+"/quotes/import?strict=true", "application/x-ndjson", strings.NewReader("{\"text\": \"\"}\n"))
+	job = waitForJob(t, client, srv.URL, job.Id)
+	require.Equal(t, services.JobFailed, job.State)
+	require.Contains(t, job.Error, "record 1: invalid quote")
+
+	res, err = client.Post(srv.URL+"/quotes/import?format=docx", "text/plain", strings.NewReader("x"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func Test_ImportJob_Cancel(t *testing.T) {
+	srv := setupImportServer(t, 1)
+	client := srv.Client()
+
+	var lines strings.Builder
+	for range 1000 {
+		fmt.Fprintf(&lines, "{\"text\": %q}\n", uuid.New().String())
+	}
+
+	// With one job at a time, the second waits for the first and is cancelled before it stores anything.
+	first := postImport(t, client, srv.URL+"/quotes/import", "application/x-ndjson", strings.NewReader(lines.String()))
+	second := postImport(t, client, srv.URL+"/quotes/import", "application/x-ndjson", strings.NewReader(lines.String()))
+
+	req, err := http.NewRequest(http.MethodDelete, srv.URL+"/jobs/"+second.Id, nil)
+	require.NoError(t, err)
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, res.StatusCode)
+
+	second = waitForJob(t, client, srv.URL, second.Id)
+	require.Equal(t, services.JobCancelled, second.State)
+	require.Zero(t, second.Inserted)
+
+	first = waitForJob(t, client, srv.URL, first.Id)
+	require.Equal(t, services.JobCompleted, first.State)
+	require.Equal(t, 1000, first.Inserted)
+
+	res, err = client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusConflict, res.StatusCode)
+
+	res, err = client.Get(srv.URL + "/jobs/missing")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/services"
+)
+
+var bareLineFeed = regexp.MustCompile(`[^\r]\n`)
+
+func Test_ComposeMessage_Multipart_Alternative(t *testing.T) {
+	text := "“Die Grenzen meiner Sprache bedeuten die Grenzen meiner Welt.”\n\n - Ludwig Wittgenstein, " + strings.Repeat("très long ", 10)
+	html := "<blockquote>Die Grenzen meiner Sprache bedeuten die Grenzen meiner Welt.</blockquote>"
+
+	raw, err := services.ComposeMessage("motivate@example.com", services.Email{
+		To:      []string{"anna@example.com", "Jürgen <jurgen@example.com>"},
+		Subject: "Ein Zitat für dich ✨",
+		Text:    text,
+		HTML:    html,
+	})
+	require.NoError(t, err)
+	require.False(t, bareLineFeed.Match(raw), "every line must end in CRLF")
+
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	header := message.Header
+	require.Equal(t, "<motivate@example.com>", header.Get("From"))
+	recipients, err := header.AddressList("To")
+	require.NoError(t, err)
+	require.Len(t, recipients, 2)
+	require.Equal(t, "Jürgen", recipients[1].Name)
+	require.NotContains(t, header.Get("Subject"), "✨", "the subject must be encoded")
+	subject, err := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, "Ein Zitat für dich ✨", subject)
+	date, err := header.Date()
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), date, time.Minute)
+	require.Regexp(t, `^<[0-9a-f-]+@example\.com>$`, header.Get("Message-ID"))
+	require.Equal(t, "1.0", header.Get("MIME-Version"))
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	bodies := map[string]string{}
+	for {
+		part, err := parts.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.Equal(t, "quoted-printable", part.Header.Get("Content-Transfer-Encoding"))
+
+		decoded, err := io.ReadAll(quotedprintable.NewReader(part))
+		require.NoError(t, err)
+		bodies[part.Header.Get("Content-Type")] = string(decoded)
+	}
+
+	require.Equal(t, strings.ReplaceAll(text, "\n", "\r\n"), bodies["text/plain; charset=utf-8"])
+	require.Equal(t, html, bodies["text/html; charset=utf-8"])
+}
+
+func Test_ComposeMessage_Plain_Text(t *testing.T) {
+	raw, err := services.ComposeMessage("motivate@example.com", services.Email{
+		To:      []string{"anna@example.com"},
+		Subject: "A motivating quote for you",
+		Text:    "Plain = simple.",
+	})
+	require.NoError(t, err)
+
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	require.Equal(t, "A motivating quote for you", message.Header.Get("Subject"))
+	require.Equal(t, "text/plain; charset=utf-8", message.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(quotedprintable.NewReader(message.Body))
+	require.NoError(t, err)
+	require.Equal(t, "Plain = simple.", string(body))
+}
+
+func Test_ComposeMessage_Rejects_Header_Injection(t *testing.T) {
+	_, err := services.ComposeMessage("motivate@example.com", services.Email{
+		To:      []string{"anna@example.com\r\nBcc: everyone@example.com"},
+		Subject: "Hi",
+	})
+	require.Error(t, err)
+
+	raw, err := services.ComposeMessage("motivate@example.com", services.Email{
+		To:      []string{"anna@example.com"},
+		Subject: "Hi\r\nBcc: everyone@example.com",
+	})
+	require.NoError(t, err)
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	require.Empty(t, message.Header.Get("Bcc"))
+}
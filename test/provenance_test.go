@@ -0,0 +1,120 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+func Test_CreateQuote_Records_Provenance(t *testing.T) {
+	srv := setupServerWithQuotes(t)
+	client := srv.Client()
+
+	body, _ := json.Marshal(map[string]string{"text": "Provenance matters."})
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/add", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-Client-ID", "dashboard")
+
+	before := time.Now()
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var created models.Quote
+	require.NoError(t, decodeJSON(res, &created))
+	require.Equal(t, models.SourceApi, created.Source)
+	require.Equal(t, "dashboard", created.CreatedBy)
+	require.False(t, created.CreatedAt.Before(before.Truncate(time.Second)))
+	require.Equal(t, created.CreatedAt, created.UpdatedAt)
+
+	res = doJSON(t, client, http.MethodPatch, srv.URL+"/quotes/"+created.Id, map[string]string{"author": "Someone"})
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var updated models.Quote
+	require.NoError(t, decodeJSON(res, &updated))
+	require.Equal(t, created.CreatedAt, updated.CreatedAt)
+	require.True(t, updated.UpdatedAt.After(created.UpdatedAt))
+	require.Equal(t, "dashboard", updated.CreatedBy)
+
+	res = doJSON(t, client, http.MethodPost, srv.URL+"/add", map[string]string{"text": "Nobody told me who they were."})
+	require.NoError(t, decodeJSON(res, &created))
+	require.Equal(t, "anonymous", created.CreatedBy)
+}
+
+func Test_Seeding_Records_Provenance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seed.json")
+	err := os.WriteFile(path, []byte(`[{"id": "42", "text": "From a file.", "author": "Seeder"}]`), 0o644)
+	require.NoError(t, err)
+
+	repo := repositories.NewInMemoryQuoteRepository()
+	fileReport, err := services.NewQuoteService(repo).SeedDbFromFile(path, services.SeedOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, fileReport.BatchId)
+
+	source := &fakeSource{quotes: []models.Quote{{Text: "From an API.", Author: "Fetcher", ExternalId: "ext-7"}}}
+	sourceReport, err := services.NewSourceService(repo).SeedDbFromSource(context.Background(), source)
+	require.NoError(t, err)
+	require.NotEqual(t, fileReport.BatchId, sourceReport.BatchId)
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 2)
+
+	require.Equal(t, models.SourceFile, quotes[0].Source)
+	require.Equal(t, "42", quotes[0].ExternalId)
+	require.Equal(t, fileReport.BatchId, quotes[0].BatchId)
+	require.Equal(t, "system", quotes[0].CreatedBy)
+	require.False(t, quotes[0].CreatedAt.IsZero())
+
+	require.Equal(t, "fake", quotes[1].Source)
+	require.Equal(t, "ext-7", quotes[1].ExternalId)
+	require.Equal(t, sourceReport.BatchId, quotes[1].BatchId)
+	require.Equal(t, "system", quotes[1].CreatedBy)
+}
+
+func Test_Filter_And_Purge_By_Source(t *testing.T) {
+	srv := setupServerWithQuotes(t,
+		models.Quote{Id: "1", Text: "One", Author: "A", Source: "zenquotes", BatchId: "b1"},
+		models.Quote{Id: "2", Text: "Two", Author: "B", Source: "zenquotes", BatchId: "b2"},
+		models.Quote{Id: "3", Text: "Three", Author: "C", Source: models.SourceApi, CreatedBy: "dashboard"},
+	)
+	client := srv.Client()
+
+	res, err := client.Get(srv.URL + "/quotes?source=zenquotes")
+	require.NoError(t, err)
+	var page services.QuotePage
+	require.NoError(t, decodeJSON(res, &page))
+	require.Equal(t, 2, page.Total)
+
+	res, err = client.Get(srv.URL + "/quotes?batch_id=b2")
+	require.NoError(t, err)
+	require.NoError(t, decodeJSON(res, &page))
+	require.Equal(t, 1, page.Total)
+	require.Equal(t, "2", page.Quotes[0].Id)
+
+	res = doJSON(t, client, http.MethodDelete, srv.URL+"/quotes", nil)
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	res = doJSON(t, client, http.MethodDelete, srv.URL+"/quotes?source=zenquotes", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var purged map[string]int
+	require.NoError(t, decodeJSON(res, &purged))
+	require.Equal(t, 2, purged["deleted"])
+
+	res, err = client.Get(srv.URL + "/quotes")
+	require.NoError(t, err)
+	require.NoError(t, decodeJSON(res, &page))
+	require.Equal(t, 1, page.Total)
+	require.Equal(t, "3", page.Quotes[0].Id)
+}
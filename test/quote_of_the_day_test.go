@@ -0,0 +1,126 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+func newNumberedQuotes(n int) []models.Quote {
+	quotes := []models.Quote{}
+	for i := range n {
+		quotes = append(quotes, models.Quote{Id: fmt.Sprint(i), Text: fmt.Sprintf("Quote %d", i), Author: "Author"})
+	}
+	return quotes
+}
+
+func newServiceWithQuotes(t *testing.T, quotes []models.Quote) (*services.QuoteService, *repositories.InMemoryQuoteRepository) {
+	repo := repositories.NewInMemoryQuoteRepository()
+	for _, quote := range quotes {
+		_, err := repo.Save(quote)
+		require.NoError(t, err)
+	}
+	return services.NewQuoteService(repo), repo
+}
+
+func Test_QuoteOfTheDay_Is_Stable_For_A_Date(t *testing.T) {
+	srv := setupServerWithQuotes(t, newNumberedQuotes(20)...)
+	client := srv.Client()
+
+	_, first := getFilteredQuote(t, client, srv.URL+"/quote/today?date=2025-06-01")
+	for range 5 {
+		res, quote := getFilteredQuote(t, client, srv.URL+"/quote/today?date=2025-06-01")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, first.Id, quote.Id)
+	}
+
+	res, _ := getFilteredQuote(t, client, srv.URL+"/quote/today?tz=Europe/Berlin")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func Test_QuoteOfTheDay_Uses_Date_In_Time_Zone(t *testing.T) {
+	service, _ := newServiceWithQuotes(t, newNumberedQuotes(20))
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// 23:30 UTC on March 10th is already March 11th in Berlin.
+	late := time.Date(2025, 3, 10, 23, 30, 0, 0, time.UTC)
+
+	inBerlin, err := service.GetQuoteOfTheDay(late.In(berlin))
+	require.NoError(t, err)
+	nextDay, err := service.GetQuoteOfTheDay(time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	require.Equal(t, nextDay.Id, inBerlin.Id)
+}
+
+func Test_QuoteOfTheDay_Does_Not_Repeat_Within_Window(t *testing.T) {
+	t.Setenv("QUOTE_OF_THE_DAY_WINDOW", "7")
+	service, _ := newServiceWithQuotes(t, newNumberedQuotes(60))
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	picks := []string{}
+	for d := range 120 {
+		quote, err := service.GetQuoteOfTheDay(start.AddDate(0, 0, d))
+		require.NoError(t, err)
+		picks = append(picks, quote.Id)
+	}
+
+	for i := range picks {
+		for j := i + 1; j < len(picks) && j < i+7; j++ {
+			require.NotEqual(t, picks[i], picks[j], "days %d and %d share a quote", i, j)
+		}
+	}
+}
+
+func Test_QuoteOfTheDay_Stable_When_Unrelated_Quotes_Added(t *testing.T) {
+	t.Setenv("QUOTE_OF_THE_DAY_WINDOW", "1")
+	service, repo := newServiceWithQuotes(t, newNumberedQuotes(30))
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := []string{}
+	for d := range 60 {
+		quote, err := service.GetQuoteOfTheDay(start.AddDate(0, 0, d))
+		require.NoError(t, err)
+		before = append(before, quote.Id)
+	}
+
+	_, err := repo.Save(models.Quote{Id: "new", Text: "New quote", Author: "Author"})
+	require.NoError(t, err)
+
+	changed := 0
+	for d := range 60 {
+		quote, err := service.GetQuoteOfTheDay(start.AddDate(0, 0, d))
+		require.NoError(t, err)
+		if quote.Id != before[d] {
+			require.Equal(t, "new", quote.Id)
+			changed++
+		}
+	}
+	require.Less(t, changed, 10)
+}
+
+func Test_QuoteOfTheDay_400_on_invalid_params(t *testing.T) {
+	srv := setupServerWithQuotes(t, newNumberedQuotes(3)...)
+	client := srv.Client()
+
+	for _, query := range []string{"date=01-06-2025", "date=2025-02-30", "tz=Mars/Olympus"} {
+		res, _ := getFilteredQuote(t, client, srv.URL+"/quote/today?"+query)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+	}
+}
+
+func Test_QuoteOfTheDay_404_if_no_entries(t *testing.T) {
+	srv := setupServerWithQuotes(t)
+
+	res, _ := getFilteredQuote(t, srv.Client(), srv.URL+"/quote/today")
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,23 +16,47 @@ import (
 	"github.com/danilobml/motivate/internal/models"
 	"github.com/danilobml/motivate/internal/repositories"
 	"github.com/danilobml/motivate/internal/services"
+	"github.com/danilobml/motivate/internal/templates"
 )
 
+// defaultShareTemplates are the embedded share templates, which always parse.
+func defaultShareTemplates() *templates.ShareTemplates {
+	shareTemplates, err := templates.NewShareTemplates("")
+	if err != nil {
+		panic(err)
+	}
+	return shareTemplates
+}
+
 func setupServer(isSeeded bool) (*httptest.Server, *mocks.MockMailer) {
 	// NOTE: inMemory doesn't require mocking. Should be changed if persistence is implemented
 	inMemoryRepo := repositories.NewInMemoryQuoteRepository()
 	mockService := services.NewQuoteService(inMemoryRepo)
 	mockMailer := mocks.MockMailer{}
-	router := handlers.NewQuotesRouter(mockService, &mockMailer)
-	routes := handlers.RegisterRoutes(router)
+	router := handlers.NewQuotesRouter(mockService, &mockMailer, defaultShareTemplates())
+	routes := handlers.RegisterRoutes(router, nil, nil)
 
 	if isSeeded {
-		mockService.SeedDbFromFile("./test_seed.json")
+		mockService.SeedDbFromFile("./test_seed.json", services.SeedOptions{})
 	}
 
 	return httptest.NewTLSServer(routes), &mockMailer
 }
 
+func setupServerWithQuotes(t *testing.T, quotes ...models.Quote) *httptest.Server {
+	repo := repositories.NewInMemoryQuoteRepository()
+	for _, quote := range quotes {
+		_, err := repo.Save(quote)
+		require.NoError(t, err)
+	}
+
+	router := handlers.NewQuotesRouter(services.NewQuoteService(repo), &mocks.MockMailer{}, defaultShareTemplates())
+	srv := httptest.NewTLSServer(handlers.RegisterRoutes(router, nil, nil))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
 func Test_HealthCheck(t *testing.T) {
 	srv, _ := setupServer(false)
 	defer srv.Close()
@@ -312,3 +337,88 @@ func Test_Share_Fails_400_On_EmptyRecipients(t *testing.T) {
 	require.Nil(t, mailer.To)
 	require.Empty(t, mailer.Message)
 }
+
+func Test_Share_Personalized_Quote(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+	for _, quote := range []models.Quote{
+		{Id: "1", Text: "Stay hungry, stay foolish.", Author: "Steve Jobs"},
+		{Id: "2", Text: "Well begun is half done.", Author: "Aristotle"},
+	} {
+		_, err := repo.Save(quote)
+		require.NoError(t, err)
+	}
+	mailer := &mocks.MockMailer{}
+	router := handlers.NewQuotesRouter(services.NewQuoteService(repo), mailer, defaultShareTemplates())
+	srv := httptest.NewTLSServer(handlers.RegisterRoutes(router, nil, nil))
+	defer srv.Close()
+
+	client := srv.Client()
+
+	res := doJSON(t, client, http.MethodPost, srv.URL+"/share", map[string]any{
+		"to":          []string{"bo@example.com"},
+		"quote_id":    "2",
+		"sender_name": "Ana\r\nBcc: everyone@example.com",
+		"reply_to":    "ana@example.com",
+		"message":     "  Good luck tomorrow!  ",
+	})
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	require.Equal(t, "Ana Bcc: everyone@example.com via motivate", mailer.Email.FromName)
+	require.Equal(t, "ana@example.com", mailer.Email.ReplyTo)
+	require.Equal(t, "Ana Bcc: everyone@example.com shared a motivating quote with you", mailer.Subject)
+	require.Contains(t, mailer.Email.Text, "Good luck tomorrow!\n\n\"Well begun is half done.\"")
+	require.Contains(t, mailer.Email.HTML, "Good luck tomorrow!")
+	require.Contains(t, mailer.Message, "From: \"Ana Bcc: everyone@example.com via motivate\" <"+mocks.MockSender+">\r\n")
+	require.Contains(t, mailer.Message, "Reply-To: <ana@example.com>\r\n")
+	require.NotContains(t, mailer.Message, "\r\nBcc:")
+
+	res = doJSON(t, client, http.MethodPost, srv.URL+"/share", map[string]any{"to": []string{"bo@example.com"}, "quote_id": "missing"})
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	for _, payload := range []map[string]any{
+		{"to": []string{"bo@example.com"}, "reply_to": "not-an-email"},
+		{"to": []string{"bo@example.com"}, "sender_name": strings.Repeat("a", 65)},
+		{"to": []string{"bo@example.com"}, "message": strings.Repeat("a", 1001)},
+		{"to": []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com", "f@example.com"}},
+	} {
+		res = doJSON(t, client, http.MethodPost, srv.URL+"/share", payload)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode, payload)
+	}
+}
+
+func Test_Share_Is_Rate_Limited_Per_Client(t *testing.T) {
+	t.Setenv("SHARE_RATE_LIMIT", "2")
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	client := srv.Client()
+	payload := map[string]any{"to": []string{"bo@example.com"}}
+
+	for range 2 {
+		res := doJSON(t, client, http.MethodPost, srv.URL+"/share", payload)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	}
+
+	res := doJSON(t, client, http.MethodPost, srv.URL+"/share", payload)
+	require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	require.NotEmpty(t, res.Header.Get("Retry-After"))
+
+	// Other endpoints are not limited.
+	res = doJSON(t, client, http.MethodGet, srv.URL+"/quote", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func Test_Share_Invalid_JSON_Stops_Request(t *testing.T) {
+	srv, mailer := setupServer(true)
+	defer srv.Close()
+
+	res, err := srv.Client().Post(srv.URL+"/share", "application/json", strings.NewReader(`{"to": ["a@example.com"], `))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	var body map[string]any
+	decoder := json.NewDecoder(res.Body)
+	require.NoError(t, decoder.Decode(&body))
+	require.False(t, decoder.More(), "only one error should be written")
+	require.Empty(t, mailer.Message)
+}
@@ -0,0 +1,346 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/repositories/repotest"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+func Test_InMemoryQuoteRepository_Conformance(t *testing.T) {
+	repotest.RunConformance(t, func(t *testing.T) repositories.QuoteRepository {
+		return repositories.NewInMemoryQuoteRepository()
+	})
+}
+
+func Test_InMemoryQuoteRepository_ConcurrentAccess(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+
+	const workers = 16
+	const perWorker = 200
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				id := fmt.Sprintf("%d-%d", w, i)
+				saved, err := repo.Save(models.Quote{Id: id, Text: "Text", Author: "Author"})
+				assert.NoError(t, err)
+				assert.Equal(t, id, saved.Id)
+
+				_, err = repo.Find(id)
+				assert.NoError(t, err)
+
+				quotes, err := repo.List()
+				assert.NoError(t, err)
+				assert.NotEmpty(t, quotes)
+
+				if i%2 == 0 {
+					assert.NoError(t, repo.Delete(id))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, workers*perWorker/2)
+
+	for _, quote := range quotes {
+		found, err := repo.Find(quote.Id)
+		require.NoError(t, err)
+		require.Equal(t, quote.Id, found.Id)
+	}
+}
+
+func Test_InMemoryQuoteRepository_PointersSurviveGrowth(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+
+	first, err := repo.Save(models.Quote{Id: "first", Text: "First", Author: "Author"})
+	require.NoError(t, err)
+
+	for i := range 1000 {
+		_, err := repo.Save(models.Quote{Id: fmt.Sprint(i), Text: "Text", Author: "Author"})
+		require.NoError(t, err)
+	}
+	_, err = repo.Save(models.Quote{Id: "first", Text: "Updated", Author: "Author"})
+	require.NoError(t, err)
+
+	require.Equal(t, "First", first.Text)
+}
+
+func newSqliteRepository(t *testing.T, path string) *repositories.SqliteQuoteRepository {
+	repo, err := repositories.NewSqliteQuoteRepository(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+
+	return repo
+}
+
+func Test_SqliteQuoteRepository_Conformance(t *testing.T) {
+	repotest.RunConformance(t, func(t *testing.T) repositories.QuoteRepository {
+		return newSqliteRepository(t, filepath.Join(t.TempDir(), "quotes.db"))
+	})
+}
+
+func Test_SqliteQuoteRepository_Persists_Across_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.db")
+
+	repo, err := repositories.NewSqliteQuoteRepository(path)
+	require.NoError(t, err)
+	_, err = repo.Save(models.Quote{Id: "1", Text: "Persisted", Author: "Author"})
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+
+	reopened := newSqliteRepository(t, path)
+	quote, err := reopened.Find("1")
+	require.NoError(t, err)
+	require.Equal(t, "Persisted", quote.Text)
+}
+
+func Test_SqliteQuoteRepository_Path_With_URI_Characters(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my quotes #1 100%")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	path := filepath.Join(dir, "quotes?.db")
+
+	repo := newSqliteRepository(t, path)
+	_, err := repo.Save(models.Quote{Id: "1", Text: "Persisted", Author: "Author"})
+	require.NoError(t, err)
+
+	_, err = os.Stat(path)
+	require.NoError(t, err, "the database must be created at the given path")
+}
+
+func Test_SqliteQuoteRepository_ConcurrentAccess(t *testing.T) {
+	repo := newSqliteRepository(t, filepath.Join(t.TempDir(), "quotes.db"))
+
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 25 {
+				_, err := repo.Save(models.Quote{Id: fmt.Sprintf("%d-%d", w, i), Text: "Text", Author: "Author"})
+				assert.NoError(t, err)
+				_, err = repo.List()
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 200)
+}
+
+func Test_NewQuoteRepositoryFromUrl(t *testing.T) {
+	repo, err := repositories.NewQuoteRepositoryFromUrl("memory")
+	require.NoError(t, err)
+	require.IsType(t, &repositories.InMemoryQuoteRepository{}, repo)
+
+	repo, err = repositories.NewQuoteRepositoryFromUrl("sqlite://" + filepath.Join(t.TempDir(), "quotes.db"))
+	require.NoError(t, err)
+	require.IsType(t, &repositories.SqliteQuoteRepository{}, repo)
+	repo.(*repositories.SqliteQuoteRepository).Close()
+
+	repo, err = repositories.NewQuoteRepositoryFromUrl("file://" + filepath.Join(t.TempDir(), "quotes.json"))
+	require.NoError(t, err)
+	require.IsType(t, &repositories.FileQuoteRepository{}, repo)
+	repo.(*repositories.FileQuoteRepository).Close()
+
+	_, err = repositories.NewQuoteRepositoryFromUrl("sqlite://")
+	require.Error(t, err)
+
+	_, err = repositories.NewQuoteRepositoryFromUrl("postgres://localhost/quotes")
+	require.Error(t, err)
+}
+
+func newFileRepository(t *testing.T, path string, compactEvery int) *repositories.FileQuoteRepository {
+	repo, err := repositories.NewFileQuoteRepository(path, compactEvery)
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+
+	return repo
+}
+
+func Test_FileQuoteRepository_Conformance(t *testing.T) {
+	repotest.RunConformance(t, func(t *testing.T) repositories.QuoteRepository {
+		return newFileRepository(t, filepath.Join(t.TempDir(), "quotes.json"), 2)
+	})
+}
+
+func Test_FileQuoteRepository_Replays_Journal_Without_Close(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+
+	// No Close: simulates a crash, leaving everything in the journal.
+	repo, err := repositories.NewFileQuoteRepository(path, 100)
+	require.NoError(t, err)
+	_, err = repo.Save(models.Quote{Id: "1", Text: "Kept", Author: "Author"})
+	require.NoError(t, err)
+	_, err = repo.Save(models.Quote{Id: "2", Text: "Deleted", Author: "Author"})
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete("2"))
+
+	reopened := newFileRepository(t, path, 100)
+	quotes, err := reopened.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 1)
+	require.Equal(t, "Kept", quotes[0].Text)
+}
+
+func Test_FileQuoteRepository_Compaction_Keeps_Triggering_Entry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+
+	// The second Save triggers compaction; without Close, the snapshot alone must hold both.
+	repo, err := repositories.NewFileQuoteRepository(path, 2)
+	require.NoError(t, err)
+	_, err = repo.Save(models.Quote{Id: "1", Text: "First", Author: "Author"})
+	require.NoError(t, err)
+	_, err = repo.Save(models.Quote{Id: "2", Text: "Second", Author: "Author"})
+	require.NoError(t, err)
+
+	reopened := newFileRepository(t, path, 2)
+	quotes, err := reopened.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 2)
+}
+
+func Test_FileQuoteRepository_ReadOnly_Leaves_Files_Untouched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+
+	// No Close, so the quotes are only in the journal.
+	repo, err := repositories.NewFileQuoteRepository(path, 100)
+	require.NoError(t, err)
+	_, err = repo.Save(models.Quote{Id: "1", Text: "Journaled", Author: "Author"})
+	require.NoError(t, err)
+
+	snapshot, err := os.ReadFile(path)
+	require.NoError(t, err)
+	journal, err := os.ReadFile(path + ".log")
+	require.NoError(t, err)
+
+	readOnly, err := repositories.OpenQuoteRepositoryReadOnly("file://" + path)
+	require.NoError(t, err)
+	quotes, err := readOnly.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 1)
+
+	_, err = readOnly.Save(models.Quote{Id: "2", Text: "Text", Author: "Author"})
+	require.ErrorIs(t, err, errs.ErrClosed)
+	require.NoError(t, readOnly.(*repositories.FileQuoteRepository).Close())
+
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, snapshot, after)
+	after, err = os.ReadFile(path + ".log")
+	require.NoError(t, err)
+	require.Equal(t, journal, after)
+
+	_, err = repositories.OpenQuoteRepositoryReadOnly("file://" + filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+	_, err = repositories.OpenQuoteRepositoryReadOnly("memory")
+	require.ErrorContains(t, err, "memory storage")
+}
+
+func Test_SqliteQuoteRepository_ReadOnly_Leaves_File_Untouched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.db")
+
+	repo, err := repositories.NewSqliteQuoteRepository(path)
+	require.NoError(t, err)
+	_, err = repo.Save(models.Quote{Id: "1", Text: "Stored", Author: "Author"})
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	readOnly, err := repositories.OpenQuoteRepositoryReadOnly("sqlite://" + path)
+	require.NoError(t, err)
+	quotes, err := readOnly.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 1)
+
+	_, err = readOnly.Save(models.Quote{Id: "2", Text: "Text", Author: "Author"})
+	require.Error(t, err)
+
+	seedPath := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(seedPath, []byte(`[{"text":"Checked only","author":"Author"}]`), 0o644))
+	report, err := services.NewQuoteService(readOnly).SeedDbFromFile(seedPath, services.SeedOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Inserted)
+	require.NoError(t, readOnly.(*repositories.SqliteQuoteRepository).Close())
+
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+
+	missing := filepath.Join(t.TempDir(), "missing.db")
+	_, err = repositories.OpenQuoteRepositoryReadOnly("sqlite://" + missing)
+	require.Error(t, err)
+	_, err = os.Stat(missing)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	unmigrated := filepath.Join(t.TempDir(), "empty.db")
+	require.NoError(t, os.WriteFile(unmigrated, nil, 0o644))
+	_, err = repositories.OpenQuoteRepositoryReadOnly("sqlite://" + unmigrated)
+	require.ErrorContains(t, err, "schema version 0")
+}
+
+func Test_FileQuoteRepository_Ignores_Partial_Journal_Line(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+
+	repo, err := repositories.NewFileQuoteRepository(path, 100)
+	require.NoError(t, err)
+	_, err = repo.Save(models.Quote{Id: "1", Text: "Kept", Author: "Author"})
+	require.NoError(t, err)
+
+	journal, err := os.OpenFile(path+".log", os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	_, err = journal.WriteString(`{"op":"save","quote":{"id":"2","te`)
+	require.NoError(t, err)
+	require.NoError(t, journal.Close())
+
+	reopened := newFileRepository(t, path, 100)
+	_, err = reopened.Save(models.Quote{Id: "3", Text: "After crash", Author: "Author"})
+	require.NoError(t, err)
+
+	again := newFileRepository(t, path, 100)
+	quotes, err := again.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 2)
+	require.Equal(t, "1", quotes[0].Id)
+	require.Equal(t, "3", quotes[1].Id)
+}
+
+func Test_FileQuoteRepository_Snapshot_Is_A_Seed_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+
+	repo, err := repositories.NewFileQuoteRepository(path, 100)
+	require.NoError(t, err)
+	_, err = repo.Save(models.Quote{Id: "1", Text: "Snapshot Text", Author: "Snapshot Author"})
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+
+	seeded := repositories.NewInMemoryQuoteRepository()
+	_, err = services.NewQuoteService(seeded).SeedDbFromFile(path, services.SeedOptions{})
+	require.NoError(t, err)
+
+	quote, err := seeded.Find("1")
+	require.NoError(t, err)
+	require.Equal(t, "Snapshot Text", quote.Text)
+	require.Equal(t, "Snapshot Author", quote.Author)
+}
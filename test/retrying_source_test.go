@@ -0,0 +1,136 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/repositories"
+)
+
+var fastRetries = repositories.RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// newFlakyServer fails the first failures requests with status and then serves a quote.
+func newFlakyServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if calls.Add(1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`[{"q": "Retried and served.", "a": "Flaky"}]`))
+	}))
+	t.Cleanup(api.Close)
+
+	return api, &calls
+}
+
+func Test_RetryingSource_Retries_Transient_Errors(t *testing.T) {
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		api, calls := newFlakyServer(t, 2, status, "")
+		source := repositories.NewRetryingSource(repositories.NewZenQuoteRepository(api.URL, nil), fastRetries, nil)
+
+		quotes, err := source.Fetch(context.Background())
+		require.NoError(t, err)
+		require.Len(t, quotes, 1)
+		require.Equal(t, int32(3), calls.Load())
+		require.Equal(t, "zenquotes", source.Name())
+	}
+}
+
+func Test_RetryingSource_Gives_Up_After_MaxAttempts(t *testing.T) {
+	api, calls := newFlakyServer(t, 100, http.StatusBadGateway, "")
+	source := repositories.NewRetryingSource(repositories.NewZenQuoteRepository(api.URL, nil), fastRetries, nil)
+
+	_, err := source.Fetch(context.Background())
+	require.ErrorIs(t, err, errs.ErrBadUpstreamResponse)
+	require.Equal(t, int32(4), calls.Load())
+}
+
+func Test_RetryingSource_Does_Not_Retry_Client_Errors(t *testing.T) {
+	api, calls := newFlakyServer(t, 100, http.StatusNotFound, "")
+	source := repositories.NewRetryingSource(repositories.NewZenQuoteRepository(api.URL, nil), fastRetries, nil)
+
+	_, err := source.Fetch(context.Background())
+	require.ErrorIs(t, err, errs.ErrBadUpstreamResponse)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func Test_RetryingSource_Honours_RetryAfter(t *testing.T) {
+	api, calls := newFlakyServer(t, 1, http.StatusTooManyRequests, "1")
+	source := repositories.NewRetryingSource(repositories.NewZenQuoteRepository(api.URL, nil), fastRetries, nil)
+
+	start := time.Now()
+	_, err := source.Fetch(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func Test_RetryingSource_Stops_When_RetryAfter_Exceeds_MaxDelay(t *testing.T) {
+	api, calls := newFlakyServer(t, 1, http.StatusTooManyRequests, "3600")
+	source := repositories.NewRetryingSource(repositories.NewZenQuoteRepository(api.URL, nil), fastRetries, nil)
+
+	_, err := source.Fetch(context.Background())
+	require.ErrorIs(t, err, errs.ErrRateLimited)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func Test_RetryingSource_Stops_On_Context_Cancel(t *testing.T) {
+	api, _ := newFlakyServer(t, 100, http.StatusServiceUnavailable, "")
+	policy := repositories.RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: time.Minute}
+	source := repositories.NewRetryingSource(repositories.NewZenQuoteRepository(api.URL, nil), policy, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := source.Fetch(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func Test_RateLimiter_Paces_Requests(t *testing.T) {
+	limiter := repositories.NewRateLimiter(2, 200*time.Millisecond)
+
+	start := time.Now()
+	for range 2 {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+	require.Less(t, time.Since(start), 50*time.Millisecond)
+
+	require.NoError(t, limiter.Wait(context.Background()))
+	require.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, limiter.Wait(ctx), context.Canceled)
+}
+
+func Test_RetryingSource_Uses_Limiter(t *testing.T) {
+	api, calls := newFlakyServer(t, 0, http.StatusOK, "")
+	limiter := repositories.NewRateLimiter(1, 100*time.Millisecond)
+	source := repositories.NewRetryingSource(repositories.NewZenQuoteRepository(api.URL, nil), fastRetries, limiter)
+
+	start := time.Now()
+	for range 3 {
+		_, err := source.Fetch(context.Background())
+		require.NoError(t, err)
+	}
+	require.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+	require.Equal(t, int32(3), calls.Load())
+}
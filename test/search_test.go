@@ -0,0 +1,94 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/search"
+)
+
+func Test_Stem_Porter_Vocabulary(t *testing.T) {
+	cases := map[string]string{
+		"caresses": "caress", "ponies": "poni", "cats": "cat", "agreed": "agre",
+		"plastered": "plaster", "motoring": "motor", "sing": "sing", "conflated": "conflat",
+		"hopping": "hop", "falling": "fall", "filing": "file", "happy": "happi",
+		"relational": "relat", "conditional": "condit", "generalization": "gener",
+		"triplicate": "triplic", "hopeful": "hope", "goodness": "good", "allowance": "allow",
+		"adjustment": "adjust", "adoption": "adopt", "effective": "effect", "controlling": "control",
+		"connections": "connect", "connected": "connect", "connecting": "connect",
+		"a": "a", "élan": "élan",
+	}
+
+	for word, stem := range cases {
+		require.Equal(t, stem, search.Stem(word), word)
+	}
+}
+
+func Test_Terms_Fold_Case_Diacritics_And_StopWords(t *testing.T) {
+	require.Equal(t, []string{"et", "naiv", "cafe"}, search.Terms("L'Été is the NAÏVE café"))
+}
+
+func Test_Index_Ranks_With_BM25(t *testing.T) {
+	index := search.NewIndex()
+	index.Add("short", "Courage is grace under pressure.")
+	index.Add("long", "Courage, courage and more courage: the world belongs to the courageous, who act with grace.")
+	index.Add("other", "Simplicity is the ultimate sophistication.")
+
+	hits := index.Search("courage", 10)
+	require.Len(t, hits, 2)
+	require.Equal(t, "long", hits[0].Id)
+	require.Equal(t, "short", hits[1].Id)
+
+	// A rarer term weighs more than a common one.
+	hits = index.Search("grace simplicity", 10)
+	require.Equal(t, "other", hits[0].Id)
+
+	require.Len(t, index.Search("courage", 1), 1)
+
+	index.Remove("long")
+	hits = index.Search("courage", 10)
+	require.Len(t, hits, 1)
+	require.Equal(t, "short", hits[0].Id)
+}
+
+func Test_SearchQuotes_Endpoint(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	client := srv.Client()
+
+	res := doJSON(t, client, http.MethodGet, srv.URL+"/quotes/search?q=fearing+darkness", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var results []models.SearchResult
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&results))
+	require.Len(t, results, 1)
+	require.Equal(t, "76", results[0].Quote.Id)
+
+	res = doJSON(t, client, http.MethodGet, srv.URL+"/quotes/search?q=tzu", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&results))
+	require.Len(t, results, 1)
+	require.Equal(t, "97", results[0].Quote.Id)
+
+	res = doJSON(t, client, http.MethodGet, srv.URL+"/quotes/search?q=unicorns", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&results))
+	require.Empty(t, results)
+}
+
+func Test_SearchQuotes_400_on_invalid_params(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	client := srv.Client()
+
+	for _, query := range []string{"", "q=%20", "q=fear&limit=0", "q=fear&limit=x"} {
+		res := doJSON(t, client, http.MethodGet, srv.URL+"/quotes/search?"+query, nil)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+	}
+}
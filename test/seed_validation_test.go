@@ -0,0 +1,113 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/errs"
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+// writeSeedLines writes one NDJSON record per line, raw strings kept as they are.
+func writeSeedLines(t *testing.T, records ...any) string {
+	t.Helper()
+
+	lines := []string{}
+	for _, record := range records {
+		if raw, ok := record.(string); ok {
+			lines = append(lines, raw)
+			continue
+		}
+		line, err := json.Marshal(record)
+		require.NoError(t, err)
+		lines = append(lines, string(line))
+	}
+
+	path := filepath.Join(t.TempDir(), "seed.ndjson")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644))
+
+	return path
+}
+
+func mixedSeedFile(t *testing.T) string {
+	tooManyTags := make([]string, 17)
+	for i := range tooManyTags {
+		tooManyTags[i] = strings.Repeat("t", i+1)
+	}
+
+	return writeSeedLines(t,
+		map[string]any{"text": "A perfectly fine quote.", "author": "Fine"},
+		map[string]any{"text": strings.Repeat("a", 513), "author": "Verbose"},
+		map[string]any{"text": "   ", "author": "Silent"},
+		map[string]any{"text": "Signed at length.", "author": strings.Repeat("b", 129)},
+		map[string]any{"text": "Over-tagged.", "tags": tooManyTags},
+		`{"text": "broken`,
+		map[string]any{"text": "Another fine quote.", "author": "Fine"},
+	)
+}
+
+func Test_SeedDbFromFile_Reports_Invalid_Records(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+
+	report, err := services.NewQuoteService(repo).SeedDbFromFile(mixedSeedFile(t), services.SeedOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Inserted)
+	require.Equal(t, 5, report.Invalid)
+
+	records := []int{}
+	for _, problem := range report.Problems {
+		records = append(records, problem.Record)
+	}
+	require.Equal(t, []int{2, 3, 4, 5, 6}, records)
+	require.Contains(t, report.Problems[0].Reason, "text is longer than 512 characters")
+	require.Contains(t, report.Problems[1].Reason, "text is required")
+	require.Contains(t, report.Problems[2].Reason, "author is longer than 128 characters")
+	require.Contains(t, report.Problems[3].Reason, "more than 16 tags")
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 2)
+}
+
+func Test_SeedDbFromFile_DryRun_Does_Not_Store(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+	_, err := repo.Save(models.Quote{Id: "existing", Text: "Already stored.", Author: "Someone"})
+	require.NoError(t, err)
+
+	path := writeSeedLines(t,
+		map[string]any{"text": "Already stored."},
+		map[string]any{"text": "New in this file."},
+		map[string]any{"text": "new in this file"},
+		map[string]any{"text": ""},
+	)
+
+	report, err := services.NewQuoteService(repo).SeedDbFromFile(path, services.SeedOptions{DryRun: true})
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Equal(t, 1, report.Inserted)
+	require.Equal(t, 2, report.Duplicates)
+	require.Equal(t, 1, report.Invalid)
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 1)
+}
+
+func Test_SeedDbFromFile_Strict_Aborts_Without_Storing(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+
+	_, err := services.NewQuoteService(repo).SeedDbFromFile(mixedSeedFile(t), services.SeedOptions{Strict: true})
+	require.ErrorIs(t, err, errs.ErrInvalidQuote)
+	require.Contains(t, err.Error(), "record 2")
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Empty(t, quotes)
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/templates"
+)
+
+func Test_ShareTemplates_Defaults(t *testing.T) {
+	rendered, err := defaultShareTemplates().Render(templates.ShareData{
+		Quote:  "Less is <em>more</em>.",
+		Author: "Mies & co",
+		Book:   "Notes",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "A motivating quote for you", rendered.Subject)
+	require.Equal(t, "\"Less is <em>more</em>.\"\n\n - Mies & co, Notes\n", rendered.Text)
+	require.Contains(t, rendered.HTML, "Less is &lt;em&gt;more&lt;/em&gt;.")
+	require.Contains(t, rendered.HTML, "Mies &amp; co")
+
+	rendered, err = defaultShareTemplates().Render(templates.ShareData{
+		Quote:      "Keep going.",
+		Author:     "Unknown",
+		SenderName: "Ana",
+		Message:    "Thought of you.",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "Ana shared a motivating quote with you", rendered.Subject)
+	require.Equal(t, "Thought of you.\n\n\"Keep going.\"\n\n - Unknown\n\nShared with you by Ana.\n", rendered.Text)
+	require.Contains(t, rendered.HTML, "Thought of you.")
+	require.Contains(t, rendered.HTML, "Shared with you by Ana.")
+}
+
+func Test_ShareTemplates_Directory_Overrides_Defaults(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "share_subject.txt"), []byte("Today's words from\n{{.Author}}"), 0o644)
+	require.NoError(t, err)
+
+	shareTemplates, err := templates.NewShareTemplates(dir)
+	require.NoError(t, err)
+
+	rendered, err := shareTemplates.Render(templates.ShareData{Quote: "Keep going.", Author: "Unknown"})
+	require.NoError(t, err)
+	require.Equal(t, "Today's words from Unknown", rendered.Subject)
+	require.Contains(t, rendered.Text, "Keep going.")
+
+	err = os.WriteFile(filepath.Join(dir, "share.html"), []byte("{{.Quote"), 0o644)
+	require.NoError(t, err)
+	_, err = templates.NewShareTemplates(dir)
+	require.Error(t, err)
+}
+
+func Test_SharePreview(t *testing.T) {
+	srv := setupServerWithQuotes(t, models.Quote{Id: "1", Text: "Stay hungry, stay foolish.", Author: "Steve Jobs"})
+	client := srv.Client()
+
+	query := url.Values{"quote_id": {"1"}, "sender_name": {"Ana"}, "message": {"For your first day."}}
+	res, err := client.Get(srv.URL + "/share/preview?" + query.Encode())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "text/html; charset=utf-8", res.Header.Get("Content-Type"))
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "Stay hungry, stay foolish.")
+	require.Contains(t, string(body), "For your first day.")
+
+	query.Set("format", "text")
+	res, err = client.Get(srv.URL + "/share/preview?" + query.Encode())
+	require.NoError(t, err)
+	body, err = io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "For your first day.\n\n\"Stay hungry, stay foolish.\"\n\n - Steve Jobs\n\nShared with you by Ana.\n", string(body))
+
+	res, err = client.Get(srv.URL + "/share/preview?format=json")
+	require.NoError(t, err)
+	var rendered templates.RenderedEmail
+	require.NoError(t, decodeJSON(res, &rendered))
+	require.Equal(t, "A motivating quote for you", rendered.Subject)
+	require.Contains(t, rendered.HTML, "Steve Jobs")
+
+	res, err = client.Get(srv.URL + "/share/preview?quote_id=missing")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	res, err = client.Get(srv.URL + "/share/preview?format=pdf")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
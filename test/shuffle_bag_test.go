@@ -0,0 +1,137 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+func Test_ShuffleBag_Cycles_Before_Repeating(t *testing.T) {
+	service, _ := newServiceWithQuotes(t, newNumberedQuotes(5))
+
+	previous := ""
+	for range 10 {
+		seen := map[string]bool{}
+		for range 5 {
+			quote, err := service.GetNextQuoteForClient("client", services.QuoteFilter{})
+			require.NoError(t, err)
+			require.False(t, seen[quote.Id], "quote %s repeated within a cycle", quote.Id)
+			require.NotEqual(t, previous, quote.Id, "quote %s served twice in a row", quote.Id)
+			seen[quote.Id] = true
+			previous = quote.Id
+		}
+	}
+}
+
+func Test_ShuffleBag_Follows_Collection_Changes(t *testing.T) {
+	service, repo := newServiceWithQuotes(t, newNumberedQuotes(5))
+
+	served := map[string]bool{}
+	for range 2 {
+		quote, err := service.GetNextQuoteForClient("client", services.QuoteFilter{})
+		require.NoError(t, err)
+		served[quote.Id] = true
+	}
+
+	// Delete the quotes served so far and add a new one mid-cycle.
+	for id := range served {
+		require.NoError(t, repo.Delete(id))
+	}
+	_, err := repo.Save(models.Quote{Id: "new", Text: "New quote", Author: "Author"})
+	require.NoError(t, err)
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	current := map[string]bool{}
+	for _, quote := range quotes {
+		current[quote.Id] = true
+	}
+
+	// A new cycle starts over the current collection: deleted quotes never come back.
+	drawn := map[string]bool{}
+	for range len(current) {
+		quote, err := service.GetNextQuoteForClient("client", services.QuoteFilter{})
+		require.NoError(t, err)
+		require.False(t, drawn[quote.Id], "quote %s repeated within a cycle", quote.Id)
+		drawn[quote.Id] = true
+	}
+	require.Equal(t, current, drawn)
+}
+
+func Test_ShuffleBag_Respects_Filters(t *testing.T) {
+	service, _ := newServiceWithQuotes(t, filterQuotes)
+
+	for range 10 {
+		quote, err := service.GetNextQuoteForClient("client", services.QuoteFilter{Author: "seneca"})
+		require.NoError(t, err)
+		require.Contains(t, []string{"1", "2"}, quote.Id)
+	}
+}
+
+func Test_ShuffleBag_Keeps_Bounded_Number_Of_Clients(t *testing.T) {
+	t.Setenv("SHUFFLE_BAG_CLIENTS", "2")
+	service, _ := newServiceWithQuotes(t, newNumberedQuotes(3))
+
+	for _, client := range []string{"a", "b", "c", "a", "d"} {
+		_, err := service.GetNextQuoteForClient(client, services.QuoteFilter{})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 2, service.ShuffleBagClients())
+}
+
+func Test_GetRandomQuote_Uses_ShuffleBag_Per_Client(t *testing.T) {
+	srv := setupServerWithQuotes(t, newNumberedQuotes(4)...)
+	client := srv.Client()
+
+	for _, header := range []string{"X-Client-ID", "X-API-Key"} {
+		seen := map[string]bool{}
+		for range 4 {
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"/quote", nil)
+			require.NoError(t, err)
+			req.Header.Set(header, "dashboard")
+
+			res, err := client.Do(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			require.Empty(t, res.Cookies())
+
+			var quote struct{ Id string }
+			require.NoError(t, decodeJSON(res, &quote))
+			require.False(t, seen[quote.Id], "quote %s repeated for %s", quote.Id, header)
+			seen[quote.Id] = true
+		}
+	}
+}
+
+func Test_GetRandomQuote_Sets_Client_Cookie_For_Anonymous_Callers(t *testing.T) {
+	srv := setupServerWithQuotes(t, newNumberedQuotes(4)...)
+	client := srv.Client()
+
+	res, err := client.Get(srv.URL + "/quote")
+	require.NoError(t, err)
+	res.Body.Close()
+
+	cookies := res.Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, "motivate_client", cookies[0].Name)
+
+	seen := map[string]bool{}
+	for range 4 {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/quote", nil)
+		require.NoError(t, err)
+		req.AddCookie(cookies[0])
+
+		res, err := client.Do(req)
+		require.NoError(t, err)
+
+		var quote struct{ Id string }
+		require.NoError(t, decodeJSON(res, &quote))
+		require.False(t, seen[quote.Id], "quote %s repeated for cookie client", quote.Id)
+		seen[quote.Id] = true
+	}
+}
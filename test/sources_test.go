@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+func newJSONServer(t *testing.T, body string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+type fakeSource struct {
+	quotes []models.Quote
+}
+
+func (fs *fakeSource) Name() string {
+	return "fake"
+}
+
+func (fs *fakeSource) Fetch(ctx context.Context) ([]models.Quote, error) {
+	return fs.quotes, nil
+}
+
+func Test_ZenQuoteRepository_Fetch(t *testing.T) {
+	api := newJSONServer(t, `[{"q": "Well done is better than well said.", "a": "Benjamin Franklin", "h": "<blockquote>...</blockquote>"}]`)
+
+	source := repositories.NewZenQuoteRepository(api.URL, nil)
+	require.Equal(t, "zenquotes", source.Name())
+
+	quotes, err := source.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []models.Quote{{Text: "Well done is better than well said.", Author: "Benjamin Franklin"}}, quotes)
+}
+
+func Test_QuotableRepository_Fetch(t *testing.T) {
+	api := newJSONServer(t, `[{"_id": "abc", "content": "Be yourself.", "author": "Oscar Wilde", "tags": ["Famous Quotes"], "length": 12}]`)
+
+	source := repositories.NewQuotableRepository(api.URL, nil)
+	require.Equal(t, "quotable", source.Name())
+
+	quotes, err := source.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, quotes, 1)
+	require.Equal(t, "Be yourself.", quotes[0].Text)
+	require.Equal(t, "Oscar Wilde", quotes[0].Author)
+	require.Equal(t, []string{"Famous Quotes"}, quotes[0].Tags)
+	require.Equal(t, "abc", quotes[0].ExternalId)
+}
+
+func Test_DummyJsonRepository_Fetch(t *testing.T) {
+	api := newJSONServer(t, `{"quotes": [{"id": 1, "quote": "Life isn't about getting and having.", "author": "Kevin Kruse"}], "total": 1, "skip": 0, "limit": 1}`)
+
+	source := repositories.NewDummyJsonRepository(api.URL, nil)
+	require.Equal(t, "dummyjson", source.Name())
+
+	quotes, err := source.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []models.Quote{{Text: "Life isn't about getting and having.", Author: "Kevin Kruse", ExternalId: "1"}}, quotes)
+}
+
+func Test_Sources_Fail_on_Malformed_Body(t *testing.T) {
+	api := newJSONServer(t, `<html>nope</html>`)
+
+	sources := []repositories.QuoteSource{
+		repositories.NewZenQuoteRepository(api.URL, nil),
+		repositories.NewQuotableRepository(api.URL, nil),
+		repositories.NewDummyJsonRepository(api.URL, nil),
+	}
+	for _, source := range sources {
+		_, err := source.Fetch(context.Background())
+		require.Error(t, err, source.Name())
+	}
+}
+
+func Test_NewQuoteSourceByName(t *testing.T) {
+	for _, name := range repositories.QuoteSourceNames() {
+		source, err := repositories.NewQuoteSourceByName(name)
+		require.NoError(t, err)
+		require.Equal(t, name, source.Name())
+	}
+
+	_, err := repositories.NewQuoteSourceByName("unknown")
+	require.Error(t, err)
+}
+
+func Test_SeedDbFromSource_Stores_Normalized_Quotes(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+	source := &fakeSource{quotes: []models.Quote{
+		{Text: "Anonymous wisdom.", Tags: []string{"Old Sayings"}},
+	}}
+
+	report, err := services.NewSourceService(repo).SeedDbFromSource(context.Background(), source)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Inserted)
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 1)
+	require.NotEmpty(t, quotes[0].Id)
+	require.Equal(t, "Unknown", quotes[0].Author)
+	require.Equal(t, []string{"old-sayings"}, quotes[0].Tags)
+}
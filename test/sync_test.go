@@ -0,0 +1,181 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/handlers"
+	"github.com/danilobml/motivate/internal/mocks"
+	"github.com/danilobml/motivate/internal/models"
+	"github.com/danilobml/motivate/internal/repositories"
+	"github.com/danilobml/motivate/internal/services"
+)
+
+// countingSource returns a new quote on every fetch, or blocks until ctx is done when block is set.
+type countingSource struct {
+	name    string
+	fetches atomic.Int32
+	err     error
+	block   bool
+}
+
+func (cs *countingSource) Name() string {
+	return cs.name
+}
+
+func (cs *countingSource) Fetch(ctx context.Context) ([]models.Quote, error) {
+	n := cs.fetches.Add(1)
+	if cs.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if cs.err != nil {
+		return nil, cs.err
+	}
+	return []models.Quote{{Text: fmt.Sprintf("Fetched from %s, round number %d.", cs.name, n), Author: "Counter"}}, nil
+}
+
+func Test_SyncService_SyncNow_Records_Status(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+	healthy := &countingSource{name: "healthy"}
+	broken := &countingSource{name: "broken", err: errors.New("upstream down")}
+
+	syncService := services.NewSyncService(services.NewSourceService(repo), []repositories.QuoteSource{healthy, broken}, 0)
+	syncService.SyncNow(context.Background())
+
+	status := syncService.Status()
+	require.False(t, status.Running)
+	require.Len(t, status.Sources, 2)
+
+	require.Equal(t, "healthy", status.Sources[0].Source)
+	require.NotNil(t, status.Sources[0].LastSuccess)
+	require.Empty(t, status.Sources[0].LastError)
+	require.Equal(t, 1, status.Sources[0].Inserted)
+
+	require.Equal(t, "broken", status.Sources[1].Source)
+	require.Nil(t, status.Sources[1].LastSuccess)
+	require.Equal(t, "upstream down", status.Sources[1].LastError)
+	require.NotNil(t, status.Sources[1].LastErrorAt)
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 1)
+}
+
+// editedSource serves the same quotes on every fetch; the test edits them in between.
+type editedSource struct {
+	quotes []models.Quote
+}
+
+func (es *editedSource) Name() string {
+	return "edited"
+}
+
+func (es *editedSource) Fetch(ctx context.Context) ([]models.Quote, error) {
+	return slices.Clone(es.quotes), nil
+}
+
+func Test_SyncService_Updates_Quotes_Edited_Upstream(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+	source := &editedSource{quotes: []models.Quote{
+		{Text: "Stay hungry, stay folish.", Author: "Steve Jobs", ExternalId: "e1"},
+		{Text: "Simplicity is the ultimate sophistication.", Author: "Leonardo da Vinci", ExternalId: "e2"},
+	}}
+	syncService := services.NewSyncService(services.NewSourceService(repo), []repositories.QuoteSource{source}, 0)
+
+	syncService.SyncNow(context.Background())
+	require.Equal(t, 2, syncService.Status().Sources[0].Inserted)
+	before, err := repo.List()
+	require.NoError(t, err)
+
+	source.quotes[0].Text = "Stay hungry, stay foolish."
+	source.quotes[0].Tags = []string{"life"}
+	syncService.SyncNow(context.Background())
+
+	status := syncService.Status().Sources[0]
+	require.Zero(t, status.Inserted)
+	require.Equal(t, 1, status.Updated)
+	require.Equal(t, 1, status.Duplicates)
+
+	quotes, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, quotes, 2)
+	updated, err := repo.Find(before[0].Id)
+	require.NoError(t, err)
+	require.Equal(t, "Stay hungry, stay foolish.", updated.Text)
+	require.Equal(t, []string{"life"}, updated.Tags)
+	require.Equal(t, before[0].CreatedAt, updated.CreatedAt)
+	require.True(t, updated.UpdatedAt.After(before[0].UpdatedAt))
+}
+
+func Test_SyncService_Runs_On_Schedule_Until_Stopped(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+	source := &countingSource{name: "scheduled"}
+
+	syncService := services.NewSyncService(services.NewSourceService(repo), []repositories.QuoteSource{source}, 20*time.Millisecond)
+	syncService.Start()
+	require.True(t, syncService.Status().Running)
+	require.NotNil(t, syncService.Status().NextRun)
+
+	require.Eventually(t, func() bool { return source.fetches.Load() >= 3 }, 2*time.Second, 5*time.Millisecond)
+
+	syncService.Stop()
+	require.False(t, syncService.Status().Running)
+
+	fetches := source.fetches.Load()
+	time.Sleep(60 * time.Millisecond)
+	require.Equal(t, fetches, source.fetches.Load())
+}
+
+func Test_SyncService_Stop_Cancels_Fetch_In_Progress(t *testing.T) {
+	source := &countingSource{name: "slow", block: true}
+	syncService := services.NewSyncService(services.NewSourceService(repositories.NewInMemoryQuoteRepository()), []repositories.QuoteSource{source}, 10*time.Millisecond)
+	syncService.Start()
+
+	require.Eventually(t, func() bool { return source.fetches.Load() == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		syncService.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop did not return while a fetch was in progress")
+	}
+}
+
+func Test_Admin_Sync_Status_Endpoint(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+	source := &countingSource{name: "admin"}
+	syncService := services.NewSyncService(services.NewSourceService(repo), []repositories.QuoteSource{source}, time.Hour)
+	syncService.SyncNow(context.Background())
+
+	router := handlers.NewQuotesRouter(services.NewQuoteService(repo), &mocks.MockMailer{}, defaultShareTemplates())
+	srv := httptest.NewTLSServer(handlers.RegisterRoutes(router, nil, handlers.NewAdminRouter(syncService)))
+	defer srv.Close()
+
+	res, err := srv.Client().Get(srv.URL + "/admin/sync")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var status services.SyncStatus
+	require.NoError(t, decodeJSON(res, &status))
+	require.False(t, status.Running)
+	require.Equal(t, "1h0m0s", status.Interval)
+	require.Len(t, status.Sources, 1)
+	require.Equal(t, "admin", status.Sources[0].Source)
+	require.NotNil(t, status.Sources[0].LastSuccess)
+	require.Equal(t, 1, status.Sources[0].Inserted)
+}
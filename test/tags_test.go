@@ -0,0 +1,93 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danilobml/motivate/internal/models"
+)
+
+func Test_NormalizeTags(t *testing.T) {
+	tags := models.NormalizeTags([]string{" Self  Improvement", "life", "LIFE", "", "  ", "courage"})
+	require.Equal(t, []string{"courage", "life", "self-improvement"}, tags)
+}
+
+func Test_ListTags_Counts_Seeded_Tags(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	res := doJSON(t, srv.Client(), http.MethodGet, srv.URL+"/tags", nil)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var tags []models.TagCount
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&tags))
+	require.Equal(t, []models.TagCount{
+		{Tag: "fear", Count: 2},
+		{Tag: "death", Count: 1},
+		{Tag: "self-knowledge", Count: 1},
+	}, tags)
+}
+
+func Test_CreateNewQuote_With_Tags(t *testing.T) {
+	srv, _ := setupServer(false)
+	defer srv.Close()
+
+	payload := map[string]any{"text": "Test Text", "author": "Test Author", "tags": []string{"Hard Work", "life", "Life"}}
+	res := doJSON(t, srv.Client(), http.MethodPost, srv.URL+"/add", payload)
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var quote models.Quote
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&quote))
+	require.Equal(t, []string{"hard-work", "life"}, quote.Tags)
+}
+
+func Test_CreateNewQuote_Fails_400_with_InvalidTags(t *testing.T) {
+	srv, _ := setupServer(false)
+	defer srv.Close()
+
+	client := srv.Client()
+
+	tooMany := make([]string, 17)
+	for i := range tooMany {
+		tooMany[i] = "tag"
+	}
+
+	for _, tags := range [][]string{tooMany, {""}, {strings.Repeat("a", 33)}} {
+		payload := map[string]any{"text": "Test Text", "tags": tags}
+		res := doJSON(t, client, http.MethodPost, srv.URL+"/add", payload)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	}
+}
+
+func Test_PatchQuote_Sets_Tags(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	client := srv.Client()
+
+	res := doJSON(t, client, http.MethodPatch, srv.URL+"/quotes/76", map[string]any{"tags": []string{"Courage"}})
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var quote models.Quote
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&quote))
+	require.Equal(t, []string{"courage"}, quote.Tags)
+	require.Equal(t, "Albus Dumbledore", quote.Author)
+
+	res = doJSON(t, client, http.MethodPatch, srv.URL+"/quotes/76", map[string]any{"author": "Dumbledore"})
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&quote))
+	require.Equal(t, []string{"courage"}, quote.Tags)
+}
+
+func Test_GetRandomQuote_Filters_By_Normalized_Tag(t *testing.T) {
+	srv, _ := setupServer(true)
+	defer srv.Close()
+
+	res, quote := getFilteredQuote(t, srv.Client(), srv.URL+"/quote?tag=Self%20Knowledge")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "97", quote.Id)
+}